@@ -4,12 +4,44 @@ package dto
 type CacheConfig struct {
 	// 配置哈希（可选，不传会自动计算）
 	Hash   string `json:"hash"`
-	// 引擎：redis / file
+	// 引擎：redis / file / memory / tiered
 	Engine string `json:"engine"`
+	// 编解码器：json / msgpack / gob / bytes（也可以是通过 CacheInst.RegisterCodec 注册的自定义名称）
+	Codec  string `json:"codec" default:"json"`
 	// Redis 配置
 	Redis  CacheRedisConfig `json:"redis"`
 	// 文件缓存配置
 	File   CacheFileConfig  `json:"file"`
+	// 内存缓存配置
+	Memory CacheMemoryConfig `json:"memory"`
+	// 二级缓存配置（本地内存 L1 + Redis L2，L2 使用 Redis 配置）
+	Tiered CacheTieredConfig `json:"tiered"`
+	// 压缩配置（对所有引擎生效）
+	Compress CacheCompressConfig `json:"compress"`
+	// 加密配置（对所有引擎生效）
+	Encrypt  CacheEncryptConfig  `json:"encrypt"`
+}
+
+// CacheCompressConfig - 缓存值压缩配置
+type CacheCompressConfig struct {
+	// Algorithm - 压缩算法：gzip / zstd（为空表示不压缩）
+	Algorithm string `json:"algorithm" comment:"压缩算法"`
+	// Threshold - 超过该字节数才压缩
+	Threshold int    `json:"threshold" comment:"压缩阈值" validate:"numeric" default:"1024"`
+}
+
+// CacheEncryptConfig - 缓存值加密配置（AES-GCM）
+/**
+ * 开启加密后未加密的值会被拒绝读取，防止绕过 GCM 认证写入伪造的值；
+ * 对已有数据开启加密时，可在迁移期间打开 Migrate 继续读取旧值。
+ */
+type CacheEncryptConfig struct {
+	// Key      - 密钥：16 / 24 / 32 字节的字符串，或其 base64 / hex 编码（为空表示不加密）
+	Key      string `json:"key" comment:"加密密钥"`
+	// Encoding - 密钥编码：raw / base64 / hex，为空时依次尝试 hex、base64 解码，都不是合法密钥时按原始字节使用
+	Encoding string `json:"encoding" comment:"密钥编码"`
+	// Migrate  - 是否允许读取未加密的旧值（仅在迁移期间开启）
+	Migrate  bool   `json:"migrate" comment:"迁移模式"`
 }
 
 // CacheRedisConfig - Redis 配置
@@ -26,6 +58,48 @@ type CacheRedisConfig struct {
 	Prefix   string `json:"prefix"   comment:"前缀" validate:"alphaDash,max=12" default:"INIS"`
 	// Database - 数据库
 	Database int    `json:"database" comment:"数据库索引" validate:"numeric"`
+	// Lock     - Remember 未命中时是否使用分布式锁，保证集群内只有一个实例回源
+	Lock     bool   `json:"lock"     comment:"Remember 分布式锁"`
+	// LockWait - 分布式锁的持有与等待时间（秒）
+	LockWait int    `json:"lock_wait" comment:"分布式锁等待时间" validate:"numeric" default:"10"`
+	// Mode     - 部署模式：standalone / sentinel / cluster
+	Mode     string   `json:"mode"     comment:"部署模式" default:"standalone"`
+	// Addrs    - 节点地址列表（host:port），为空时使用 Host + Port；sentinel 模式下为哨兵地址
+	Addrs    []string `json:"addrs"    comment:"节点地址列表"`
+	// MasterName - sentinel 模式下的主节点名称
+	MasterName string `json:"master_name" comment:"主节点名称" default:"mymaster"`
+	// Username - ACL 用户名
+	Username string   `json:"username" comment:"用户名"`
+	// SentinelPassword - 哨兵密码（sentinel 模式）
+	SentinelPassword string `json:"sentinel_password" comment:"哨兵密码"`
+	// TLS      - TLS 配置
+	TLS      CacheRedisTLSConfig `json:"tls"`
+	// PoolSize     - 连接池大小（0 表示使用 go-redis 默认值）
+	PoolSize     int `json:"pool_size"      comment:"连接池大小" validate:"numeric"`
+	// MinIdleConns - 最小空闲连接数
+	MinIdleConns int `json:"min_idle_conns" comment:"最小空闲连接数" validate:"numeric"`
+	// DialTimeout  - 连接超时（毫秒）
+	DialTimeout  int `json:"dial_timeout"   comment:"连接超时（毫秒）" validate:"numeric"`
+	// ReadTimeout  - 读超时（毫秒）
+	ReadTimeout  int `json:"read_timeout"   comment:"读超时（毫秒）" validate:"numeric"`
+	// WriteTimeout - 写超时（毫秒）
+	WriteTimeout int `json:"write_timeout"  comment:"写超时（毫秒）" validate:"numeric"`
+}
+
+// CacheRedisTLSConfig - Redis TLS 配置
+type CacheRedisTLSConfig struct {
+	// Enable     - 是否启用 TLS
+	Enable     bool   `json:"enable"      comment:"是否启用 TLS"`
+	// ServerName - 证书校验使用的服务器名称（为空时使用连接地址）
+	ServerName string `json:"server_name" comment:"服务器名称"`
+	// CAFile     - CA 证书路径（为空时使用系统证书）
+	CAFile     string `json:"ca_file"     comment:"CA 证书路径"`
+	// CertFile   - 客户端证书路径（双向认证）
+	CertFile   string `json:"cert_file"   comment:"客户端证书路径"`
+	// KeyFile    - 客户端私钥路径（双向认证）
+	KeyFile    string `json:"key_file"    comment:"客户端私钥路径"`
+	// Insecure   - 是否跳过证书校验（仅用于测试环境）
+	Insecure   bool   `json:"insecure"    comment:"跳过证书校验"`
 }
 
 // CacheFileConfig - 文件缓存配置
@@ -38,4 +112,32 @@ type CacheFileConfig struct {
 	Root    string `json:"root"    comment:"文件缓存根目录" default:"./runtime/cache"`
 	// Suffix  - 文件后缀
 	Suffix  string `json:"suffix"  comment:"文件后缀" default:"json"`
+	// Sweep   - 后台清理过期缓存和遗留临时文件的间隔（秒，0 表示不启用）
+	Sweep   int    `json:"sweep"   comment:"过期清理间隔" validate:"numeric" default:"0"`
+	// Shard   - 按哈希把缓存文件分散到两级子目录（如 ab/cd/INIS-xxxx.json），开启前写入的缓存将失效
+	Shard   bool   `json:"shard"   comment:"分目录存储" default:"false"`
+}
+
+// CacheMemoryConfig - 内存缓存配置
+type CacheMemoryConfig struct {
+	// Expired    - 过期时间
+	Expired    int    `json:"expired"     comment:"过期时间" validate:"numeric" default:"7200"`
+	// Prefix     - 前缀
+	Prefix     string `json:"prefix"      comment:"前缀" validate:"alphaDash,max=12" default:"INIS"`
+	// MaxEntries - 最大缓存条数（与 MaxBytes 都未配置时默认 10000）
+	MaxEntries int    `json:"max_entries" comment:"最大缓存条数" validate:"numeric" default:"10000"`
+	// MaxBytes   - 最大占用字节数（0 表示不限制）
+	MaxBytes   int64  `json:"max_bytes"   comment:"最大占用字节数" validate:"numeric"`
+}
+
+// CacheTieredConfig - 二级缓存配置
+type CacheTieredConfig struct {
+	// Expired    - 本地 L1 的最长存活时间（秒），也是跨节点失效通知丢失时的最长脏读时间
+	Expired    int    `json:"expired"     comment:"L1 过期时间" validate:"numeric" default:"60"`
+	// MaxEntries - 本地 L1 最大缓存条数
+	MaxEntries int    `json:"max_entries" comment:"L1 最大缓存条数" validate:"numeric" default:"1000"`
+	// MaxBytes   - 本地 L1 最大占用字节数（0 表示不限制）
+	MaxBytes   int64  `json:"max_bytes"   comment:"L1 最大占用字节数" validate:"numeric"`
+	// Channel    - 失效通知的 Redis 频道（为空时使用 <Prefix>-CACHE-INVALIDATE）
+	Channel    string `json:"channel"     comment:"失效通知频道"`
 }
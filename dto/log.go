@@ -10,6 +10,101 @@ type LogConfig struct {
 	Age     int    `json:"age"  default:"7"`
 	// Backups - 日志文件最大保存数量
 	Backups int    `json:"backups" default:"20"`
+	// Dir     - 日志目录，文件按 <Dir>/<日期>/<级别>.log 写入
+	Dir     string `json:"dir" default:"runtime/logs"`
+	// Console - 同时输出到控制台：stdout / stderr，为空时不输出
+	Console string `json:"console"`
+	// Format  - 日志格式：json / console
+	Format  string `json:"format" default:"json"`
+	// Compress  - 是否压缩轮转后的旧日志文件（gzip）
+	Compress  bool `json:"compress"`
+	// LocalTime - 轮转备份文件名是否使用本地时间（默认 UTC）
+	LocalTime bool `json:"local_time"`
+	// Level   - 日志级别：debug / info / warn / error，运行时可通过 LogInst.SetLevel 修改
+	Level   string `json:"level" default:"debug"`
+	// Modules - 模块级别覆盖，如 {"payment": "debug"}
+	Modules map[string]string `json:"modules"`
+	// Redact  - 敏感字段脱敏
+	Redact  LogRedactConfig `json:"redact"`
+	// Sinks   - 远程日志输出（在本地文件之外同时发送）
+	Sinks   []LogSinkConfig `json:"sinks"`
+	// Async   - 异步缓冲写入
+	Async   LogAsyncConfig `json:"async"`
+	// Sampling - debug 日志采样
+	Sampling LogSamplingConfig `json:"sampling"`
 	// Hash - 计算配置是否发生变更
 	Hash    string `json:"hash"`
+}
+
+// LogRedactConfig - 日志脱敏配置
+/**
+ * 脱敏规则：phone / email / id_card / bank_card / password（整体替换为 ******）。
+ * 启用后内置常见字段名（password、token、phone、email、id_card 等），Keys 中的同名字段会覆盖内置规则。
+ */
+type LogRedactConfig struct {
+	// Enable   - 是否启用脱敏
+	Enable   bool              `json:"enable"`
+	// Keys     - 按字段名匹配（忽略大小写、下划线和中划线），如 {"mobile": "phone"}
+	Keys     map[string]string `json:"keys"`
+	// Patterns - 按字段名正则匹配，如 {"(?i)secret$": "password"}
+	Patterns map[string]string `json:"patterns"`
+	// Detect   - 按值检测的规则：phone / email / id_card / bank_card，检测所有字符串值
+	Detect   []string          `json:"detect"`
+}
+
+
+// LogAsyncConfig - 异步缓冲写入配置
+/**
+ * 开启后日志先写入内存缓冲，缓冲写满或到达刷新间隔时再落盘；
+ * 程序退出前请调用 facade.LogInst.Close()，否则缓冲中的日志会丢失。
+ */
+type LogAsyncConfig struct {
+	// Enable   - 是否启用
+	Enable   bool `json:"enable"`
+	// Size     - 每个级别的缓冲大小（字节）
+	Size     int  `json:"size" default:"262144"`
+	// Interval - 刷新间隔（毫秒）
+	Interval int  `json:"interval" default:"1000"`
+}
+
+// LogSamplingConfig - debug 日志采样配置
+/**
+ * 每秒内相同内容的 debug 日志，前 Initial 条全部输出，之后每 Thereafter 条输出一条。
+ */
+type LogSamplingConfig struct {
+	// Enable     - 是否启用
+	Enable     bool `json:"enable"`
+	// Initial    - 每秒全部输出的条数
+	Initial    int  `json:"initial" default:"100"`
+	// Thereafter - 超出后每多少条输出一条
+	Thereafter int  `json:"thereafter" default:"100"`
+}
+
+// LogSinkConfig - 远程日志输出配置
+/**
+ * 每个输出都有独立的异步缓冲队列，队列满时丢弃新日志并计数，不会阻塞业务代码。
+ */
+type LogSinkConfig struct {
+	// Type     - 类型：syslog（RFC5424）/ http（批量 POST JSON Lines）/ tcp（JSON 流）
+	Type     string `json:"type"`
+	// Addr     - 地址：syslog、tcp 为 host:port，http 为完整 URL
+	Addr     string `json:"addr"`
+	// Network  - syslog 使用的协议：udp / tcp
+	Network  string `json:"network" default:"udp"`
+	// Level    - 最低输出级别
+	Level    string `json:"level" default:"debug"`
+	// Tag      - syslog 的 APP-NAME，默认为程序名
+	Tag      string `json:"tag"`
+	// Buffer   - 缓冲队列长度（条）
+	Buffer   int    `json:"buffer" default:"1024"`
+	// Batch    - http 每批最多发送的条数
+	Batch    int    `json:"batch" default:"100"`
+	// Interval - http 批量发送间隔（毫秒）
+	Interval int    `json:"interval" default:"1000"`
+	// Retry    - http 发送失败的重试次数（指数退避），-1 表示不重试
+	Retry    int    `json:"retry" default:"3"`
+	// Timeout  - 连接与发送超时（毫秒）
+	Timeout  int    `json:"timeout" default:"5000"`
+	// Headers  - http 请求头
+	Headers  map[string]string `json:"headers"`
 }
\ No newline at end of file
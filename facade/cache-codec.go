@@ -0,0 +1,209 @@
+package facade
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	json "github.com/json-iterator/go"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// CacheCodec - 缓存值编解码器
+type CacheCodec interface {
+	// Name - 编解码器名称（写入文件缓存时用于标记值的编码方式）
+	Name() string
+	// Marshal - 把值编码为字节
+	Marshal(value any) ([]byte, error)
+	// Unmarshal - 把字节解码到 value（value 必须是指针）
+	Unmarshal(data []byte, value any) error
+}
+
+// cacheCodecs - 已注册的编解码器
+var cacheCodecs = struct {
+	Mutex sync.RWMutex
+	Items map[string]CacheCodec
+}{
+	Items: map[string]CacheCodec{
+		"json":    &CacheJsonCodec{},
+		"msgpack": &CacheMsgpackCodec{},
+		"gob":     &CacheGobCodec{},
+		"bytes":   &CacheBytesCodec{},
+	},
+}
+
+// RegisterCodec - 注册自定义编解码器（同名覆盖）
+/**
+ * @param codec 编解码器
+ * @example：
+ * facade.CacheInst.RegisterCodec(&MyCodec{})
+ */
+func (this *CacheClass) RegisterCodec(codec CacheCodec) {
+
+	if codec == nil { return }
+
+	cacheCodecs.Mutex.Lock()
+	defer cacheCodecs.Mutex.Unlock()
+
+	cacheCodecs.Items[strings.ToLower(codec.Name())] = codec
+}
+
+// codec - 按名称获取编解码器，未注册时回退到 JSON
+func (this *CacheClass) codec(name string) CacheCodec {
+
+	cacheCodecs.Mutex.RLock()
+	defer cacheCodecs.Mutex.RUnlock()
+
+	if item, ok := cacheCodecs.Items[strings.ToLower(strings.TrimSpace(name))]; ok {
+		return item
+	}
+
+	return cacheCodecs.Items["json"]
+}
+
+// CacheJsonCodec - JSON 编解码器（默认）
+type CacheJsonCodec struct{}
+
+// Name - 编解码器名称
+func (this *CacheJsonCodec) Name() string { return "json" }
+
+// Marshal - 编码
+func (this *CacheJsonCodec) Marshal(value any) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+// Unmarshal - 解码
+func (this *CacheJsonCodec) Unmarshal(data []byte, value any) error {
+	return json.Unmarshal(data, value)
+}
+
+// CacheMsgpackCodec - MessagePack 编解码器
+type CacheMsgpackCodec struct{}
+
+// Name - 编解码器名称
+func (this *CacheMsgpackCodec) Name() string { return "msgpack" }
+
+// Marshal - 编码
+func (this *CacheMsgpackCodec) Marshal(value any) ([]byte, error) {
+	return msgpack.Marshal(value)
+}
+
+// Unmarshal - 解码
+func (this *CacheMsgpackCodec) Unmarshal(data []byte, value any) error {
+	return msgpack.Unmarshal(data, value)
+}
+
+// CacheGobCodec - Gob 编解码器
+/**
+ * gob 需要明确的目标类型，请通过 CacheGet[T] 读取；
+ * 使用无类型的 Get/GetCtx 时会返回错误。
+ */
+type CacheGobCodec struct{}
+
+// Name - 编解码器名称
+func (this *CacheGobCodec) Name() string { return "gob" }
+
+// Marshal - 编码
+func (this *CacheGobCodec) Marshal(value any) ([]byte, error) {
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(value); err != nil { return nil, err }
+	return buffer.Bytes(), nil
+}
+
+// Unmarshal - 解码
+func (this *CacheGobCodec) Unmarshal(data []byte, value any) error {
+	if _, ok := value.(*any); ok {
+		return fmt.Errorf("gob 编码的缓存无法解码为 any，请使用 CacheGet[T] 读取")
+	}
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(value)
+}
+
+// CacheBytesCodec - 原始字节编解码器（只接受 []byte 和 string）
+type CacheBytesCodec struct{}
+
+// Name - 编解码器名称
+func (this *CacheBytesCodec) Name() string { return "bytes" }
+
+// Marshal - 编码
+func (this *CacheBytesCodec) Marshal(value any) ([]byte, error) {
+	switch item := value.(type) {
+	case []byte:
+		return bytes.Clone(item), nil
+	case string:
+		return []byte(item), nil
+	case nil:
+		return []byte{}, nil
+	}
+	return nil, fmt.Errorf("bytes 编解码器不支持类型 %T", value)
+}
+
+// Unmarshal - 解码
+func (this *CacheBytesCodec) Unmarshal(data []byte, value any) error {
+	switch item := value.(type) {
+	case *[]byte:
+		*item = bytes.Clone(data)
+	case *string:
+		*item = string(data)
+	case *any:
+		*item = bytes.Clone(data)
+	default:
+		return fmt.Errorf("bytes 编解码器不支持类型 %T", value)
+	}
+	return nil
+}
+
+// CacheGet - 按类型读取缓存
+/**
+ * @param cache 缓存实例
+ * @param key 缓存的key
+ * @return T 缓存值, bool 是否命中, error 后端或解码错误
+ * @example：
+ * user, ok, err := facade.CacheGet[User](facade.Cache, "user:1")
+ */
+func CacheGet[T any](cache CacheAPI, key string) (value T, ok bool, err error) {
+	return CacheGetCtx[T](context.Background(), cache, key)
+}
+
+// CacheGetCtx - 按类型读取缓存（支持上下文）
+func CacheGetCtx[T any](ctx context.Context, cache CacheAPI, key string) (value T, ok bool, err error) {
+
+	if cache == nil { return value, false, fmt.Errorf("缓存未初始化") }
+
+	data, err := cache.GetRaw(ctx, key)
+	if errors.Is(err, ErrCacheMiss) { return value, false, nil }
+	if err != nil { return value, false, err }
+
+	if err = CacheInst.decode(cache.Codec(), key, data, &value); err != nil {
+		return value, false, fmt.Errorf("%w: %w", ErrCacheDecode, err)
+	}
+
+	return value, true, nil
+}
+
+// CacheSet - 按类型写入缓存（沿用链式调用上的过期时间和标签）
+/**
+ * @param cache 缓存实例
+ * @param key 缓存的key
+ * @param value 缓存的值
+ * @return error
+ * @example：
+ * err := facade.CacheSet(facade.Cache.Expired(60).Tag("user"), "user:1", user)
+ */
+func CacheSet[T any](cache CacheAPI, key string, value T) (err error) {
+	return CacheSetCtx[T](context.Background(), cache, key, value)
+}
+
+// CacheSetCtx - 按类型写入缓存（支持上下文）
+func CacheSetCtx[T any](ctx context.Context, cache CacheAPI, key string, value T) (err error) {
+
+	if cache == nil { return fmt.Errorf("缓存未初始化") }
+
+	data, err := CacheInst.encode(cache.Codec(), key, value)
+	if err != nil { return fmt.Errorf("编码缓存失败: %w", err) }
+
+	return cache.SetRaw(ctx, key, data)
+}
@@ -0,0 +1,186 @@
+package facade
+
+import (
+	"context"
+	JSON "encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// CacheEntry - 导出 / 导入的缓存条目（JSON Lines 中的一行）
+type CacheEntry struct {
+	// 原始 key
+	Key   string          `json:"key"`
+	// 剩余存活时间（毫秒，0 表示永不过期）
+	TTL   int64           `json:"ttl"`
+	// 所属标签
+	Tags  []string        `json:"tags,omitempty"`
+	// 编解码器
+	Codec string          `json:"codec"`
+	// 缓存值（JSON 编码的值直接内嵌，保持可读）
+	Value JSON.RawMessage `json:"value,omitempty"`
+	// 缓存值（其余编码为 base64 字节）
+	Data  []byte          `json:"data,omitempty"`
+}
+
+// CacheSkipError - 导出时无法还原而被跳过的缓存（其余条目已正常导出）
+type CacheSkipError struct {
+	// 被跳过的 key（没有记录原始 key 的文件缓存以文件名表示）
+	Keys []string
+	// 第一个失败原因
+	Err  error
+}
+
+func (this *CacheSkipError) Error() string {
+
+	keys := this.Keys
+	if len(keys) > 10 { keys = append(keys[:10:10], "...") }
+
+	return fmt.Sprintf("%d 条缓存无法还原，已跳过: %s: %v", len(this.Keys), strings.Join(keys, ", "), this.Err)
+}
+
+func (this *CacheSkipError) Unwrap() error { return this.Err }
+
+// cacheRecord - 引擎中的一条缓存（data 为引擎中存储的原始字节）
+type cacheRecord struct {
+	key  string
+	data []byte
+	ttl  time.Duration
+	tags []string
+}
+
+// export - 把引擎中的缓存按 JSON Lines 写出
+/**
+ * 导出的值已去掉压缩和加密，导入到其他配置的实例时会按目标实例的配置重新处理；
+ * 无法还原的条目（如密钥不一致）会被跳过，导出结束后通过 *CacheSkipError 返回这些 key。
+ */
+func (this *CacheClass) export(w io.Writer, codec CacheCodec, each func(yield func(item cacheRecord) error) error) (count int, err error) {
+
+	encoder := JSON.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+
+	skipped := &CacheSkipError{}
+
+	err = each(func(item cacheRecord) error {
+
+		// Remember 的内部标记不导出
+		if this.marker(item.key) { return nil }
+
+		entry, err := this.entry(codec, item)
+		if err != nil {
+			skipped.Keys = append(skipped.Keys, item.key)
+			if skipped.Err == nil { skipped.Err = err }
+			return nil
+		}
+
+		if err = encoder.Encode(entry); err != nil { return fmt.Errorf("写出缓存失败: %w", err) }
+
+		count++
+		return nil
+	})
+
+	// 引擎在遍历时跳过的条目（如没有原始 key 的旧文件）一并返回
+	var inner *CacheSkipError
+	if errors.As(err, &inner) {
+		skipped.Keys = append(inner.Keys, skipped.Keys...)
+		if inner.Err != nil { skipped.Err = inner.Err }
+		err = nil
+	}
+
+	if err == nil && len(skipped.Keys) > 0 { err = skipped }
+
+	return count, err
+}
+
+// load - 读取 JSON Lines 并写入缓存（沿用 cache 上的链式参数）
+func (this *CacheClass) load(cache CacheAPI, r io.Reader) (count int, err error) {
+
+	ctx     := context.Background()
+	decoder := JSON.NewDecoder(r)
+
+	for {
+
+		var entry CacheEntry
+		if err = decoder.Decode(&entry); errors.Is(err, io.EOF) { return count, nil }
+		if err != nil { return count, fmt.Errorf("解析导入数据失败: %w", err) }
+
+		data, err := this.restore(cache.Codec(), entry)
+		if err != nil { return count, fmt.Errorf("还原缓存 %s 失败: %w", entry.Key, err) }
+
+		// TTL 为 0 表示永不过期，按秒传入 0 时各引擎都会使用自身的永不过期值
+		target := cache.Expired(0)
+		if entry.TTL > 0 { target = cache.Expired(time.Duration(entry.TTL) * time.Millisecond) }
+		if len(entry.Tags) > 0 { target = target.Tags(entry.Tags) }
+
+		if err = target.SetRaw(ctx, entry.Key, data); err != nil { return count, err }
+
+		count++
+	}
+}
+
+// entry - 把引擎中的条目转换为可移植的导出格式
+func (this *CacheClass) entry(codec CacheCodec, item cacheRecord) (entry CacheEntry, err error) {
+
+	plain, err := this.openRaw(codec, item.key, item.data)
+	if err != nil { return entry, err }
+
+	entry = CacheEntry{
+		Key:   item.key,
+		Tags:  item.tags,
+		Codec: this.innerCodec(codec).Name(),
+	}
+
+	if item.ttl > 0 { entry.TTL = max(item.ttl.Milliseconds(), 1) }
+
+	if entry.Codec == "json" && JSON.Valid(plain) {
+		entry.Value = plain
+	} else {
+		entry.Data = plain
+	}
+
+	return entry, nil
+}
+
+// restore - 把导出的值转换为目标实例中存储的原始字节（编解码器不同时先转码）
+func (this *CacheClass) restore(codec CacheCodec, entry CacheEntry) (data []byte, err error) {
+
+	plain := []byte(entry.Value)
+	if len(plain) == 0 { plain = entry.Data }
+
+	inner := this.innerCodec(codec)
+
+	if entry.Codec != "" && entry.Codec != inner.Name() {
+
+		var value any
+		if err = this.codec(entry.Codec).Unmarshal(plain, &value); err != nil { return nil, err }
+		if plain, err = inner.Marshal(value); err != nil { return nil, err }
+	}
+
+	return this.sealRaw(codec, entry.Key, plain)
+}
+
+// innerCodec - 去掉压缩、加密外层后的编解码器
+func (this *CacheClass) innerCodec(codec CacheCodec) CacheCodec {
+	if item, ok := codec.(*CacheTransformCodec); ok { return item.Codec }
+	return codec
+}
+
+// openRaw - 去掉存储值上的压缩、加密
+func (this *CacheClass) openRaw(codec CacheCodec, key string, data []byte) ([]byte, error) {
+	if item, ok := codec.(*CacheTransformCodec); ok { return item.open(key, data) }
+	return data, nil
+}
+
+// sealRaw - 按编解码器的配置压缩、加密
+func (this *CacheClass) sealRaw(codec CacheCodec, key string, data []byte) ([]byte, error) {
+	if item, ok := codec.(*CacheTransformCodec); ok { return item.seal(key, data) }
+	return data, nil
+}
+
+// exportTag - 存储的标签名（TAG-XXX）转换为导出的标签名
+func (this *CacheClass) exportTag(tag string) string {
+	return strings.TrimPrefix(tag, "TAG-")
+}
@@ -0,0 +1,24 @@
+//go:build !unix && !windows
+
+package facade
+
+import (
+	"os"
+	"sync"
+)
+
+// cacheFlocks - 不支持文件锁的平台退化为进程内互斥
+var cacheFlocks sync.Map
+
+// cacheFlock - 获取排他锁（仅进程内有效）
+func cacheFlock(file *os.File) error {
+	item, _ := cacheFlocks.LoadOrStore(file.Name(), &sync.Mutex{})
+	item.(*sync.Mutex).Lock()
+	return nil
+}
+
+// cacheFunlock - 释放锁
+func cacheFunlock(file *os.File) error {
+	if item, ok := cacheFlocks.Load(file.Name()); ok { item.(*sync.Mutex).Unlock() }
+	return nil
+}
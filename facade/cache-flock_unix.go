@@ -0,0 +1,22 @@
+//go:build unix
+
+package facade
+
+import (
+	"os"
+	"syscall"
+)
+
+// cacheFlock - 获取排他文件锁（阻塞等待）
+func cacheFlock(file *os.File) error {
+	for {
+		err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX)
+		// 被信号中断时重试
+		if err != syscall.EINTR { return err }
+	}
+}
+
+// cacheFunlock - 释放文件锁
+func cacheFunlock(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+}
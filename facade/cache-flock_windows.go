@@ -0,0 +1,21 @@
+//go:build windows
+
+package facade
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// cacheFlock - 获取排他文件锁（阻塞等待）
+func cacheFlock(file *os.File) error {
+	overlapped := new(windows.Overlapped)
+	return windows.LockFileEx(windows.Handle(file.Fd()), windows.LOCKFILE_EXCLUSIVE_LOCK, 0, 1, 0, overlapped)
+}
+
+// cacheFunlock - 释放文件锁
+func cacheFunlock(file *os.File) error {
+	overlapped := new(windows.Overlapped)
+	return windows.UnlockFileEx(windows.Handle(file.Fd()), 0, 1, 0, overlapped)
+}
@@ -0,0 +1,787 @@
+package facade
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/inis-io/aide/dto"
+	"github.com/inis-io/aide/utils"
+	"github.com/spf13/cast"
+	"golang.org/x/sync/singleflight"
+)
+
+// ============================ 内存缓存 ============================
+
+// MemoryClass - 内存缓存实现（LRU 淘汰 + 按 key 过期）
+type MemoryClass struct {
+	// 存储（同一实例的所有克隆共享）
+	Store  *MemoryStore
+	// 缓存参数
+	Body   CacheBody
+	// 当前配置
+	Config dto.CacheMemoryConfig
+	// 编解码器
+	codec  CacheCodec
+	// 请求合并组
+	group  *singleflight.Group
+	// 命中计数器
+	counter *cacheCounter
+}
+
+// MemoryStore - 内存缓存存储
+type MemoryStore struct {
+	// 互斥锁，LRU 读取也会调整顺序，所以读写都需要加锁
+	mutex sync.Mutex
+	// LRU 链表，表头为最近使用
+	list  *list.List
+	// 缓存名称 -> 链表节点
+	items map[string]*list.Element
+	// 标签 -> 缓存名称集合
+	tags  map[string]map[string]struct{}
+	// 当前占用字节数
+	bytes int64
+	// 最大缓存条数
+	maxEntries int
+	// 最大占用字节数
+	maxBytes   int64
+	// 事件上报
+	counter    *cacheCounter
+	// 待上报的淘汰 / 过期事件（释放锁后再上报，避免观察者回调缓存时死锁）
+	events     []memoryEvent
+}
+
+// memoryEvent - 待上报的淘汰 / 过期事件
+type memoryEvent struct {
+	kind CacheEventType
+	key  string
+}
+
+// memoryEntry - 内存缓存条目
+type memoryEntry struct {
+	// 缓存名称
+	name    string
+	// 原始 key
+	key     string
+	// 编码后的值
+	value   []byte
+	// 过期时间（零值表示永不过期）
+	expired time.Time
+	// 所属标签
+	tags    []string
+}
+
+// size - 条目占用的字节数（近似值）
+func (this *memoryEntry) size() int64 {
+	return int64(len(this.name) + len(this.key) + len(this.value))
+}
+
+// expire - 是否已过期
+func (this *memoryEntry) expire(now time.Time) bool {
+	return !this.expired.IsZero() && now.After(this.expired)
+}
+
+// clone - 克隆内存缓存实例（共享存储，隔离链式上下文）
+func (this *MemoryClass) clone() *MemoryClass {
+
+	if this == nil { return nil }
+
+	clone := *this
+	clone.Body = CacheInst.cloneBody(this.Body)
+	return &clone
+}
+
+// NewCache - 按配置创建新的缓存实例
+func (this *MemoryClass) NewCache(config dto.CacheConfig) CacheAPI {
+	return CacheInst.newWithConfig(config)
+}
+
+// Init - 初始化内存缓存
+func (this *MemoryClass) Init(config dto.CacheMemoryConfig) {
+
+	this.Config = config
+
+	prefix := this.Config.Prefix
+	if !utils.Is.Empty(prefix) {
+		this.Body.Prefix = prefix
+	}
+
+	this.counter = &cacheCounter{engine: "memory"}
+	this.Store = &MemoryStore{
+		list:       list.New(),
+		items:      make(map[string]*list.Element),
+		tags:       make(map[string]map[string]struct{}),
+		maxEntries: this.Config.MaxEntries,
+		maxBytes:   this.Config.MaxBytes,
+		counter:    this.counter,
+	}
+	this.Body.Expired = time.Duration(this.Config.Expired) * time.Second
+	this.group   = &singleflight.Group{}
+}
+
+// Key - 键
+func (this *MemoryClass) Key(key ...string) CacheAPI {
+	cache := this.clone()
+	var keys []string
+	// 把多个标签转换为数组
+	for _, value := range key {
+		keys = append(keys, cache.Name(value))
+	}
+	// 合并之前的标签
+	if len(cache.Body.Keys) > 0 {
+		keys = append(keys, cache.Body.Keys...)
+	}
+	// 去重
+	cache.Body.Keys = cast.ToStringSlice(utils.ArrayUnique(keys))
+	return cache
+}
+
+// Keys - 键集
+func (this *MemoryClass) Keys(key []string) CacheAPI {
+	return this.Key(key...)
+}
+
+// Tag - 标签
+func (this *MemoryClass) Tag(tag ...string) CacheAPI {
+	cache := this.clone()
+	var tags []string
+	// 把多个标签转换为数组
+	for _, value := range tag {
+		tags = append(tags, strings.ToUpper(fmt.Sprintf("tag-%v", value)))
+	}
+	// 合并之前的标签
+	if len(cache.Body.Tags) > 0 {
+		tags = append(tags, cache.Body.Tags...)
+	}
+	// 去重
+	cache.Body.Tags = cast.ToStringSlice(utils.ArrayUnique(tags))
+	return cache
+}
+
+// Tags - 标签
+func (this *MemoryClass) Tags(tag []string) CacheAPI {
+	return this.Tag(tag...)
+}
+
+// Expired - 过期时间
+func (this *MemoryClass) Expired(second any) CacheAPI {
+
+	cache := this.clone()
+
+	// 判断 second 是否为 time.Duration 类型或可解析为 duration 字符串
+	switch v := second.(type) {
+	case time.Duration:
+		cache.Body.Expired = v
+	case string:
+		// 尝试解析为 duration 字符串（例如 `5s`, `1m`）
+		if d, err := time.ParseDuration(v); err == nil {
+			cache.Body.Expired = d
+		} else if i := cast.ToInt64(v); i > 0 {
+			cache.Body.Expired = time.Duration(i) * time.Second
+		} else {
+			cache.Body.Expired = cast.ToDuration(v)
+		}
+	default:
+		// 对于数值类型，按秒处理；对于其他类型，尽量转换为 duration
+		if cast.ToInt64(second) > 0 {
+			cache.Body.Expired = time.Duration(cast.ToInt64(second)) * time.Second
+		} else {
+			cache.Body.Expired = cast.ToDuration(second)
+		}
+	}
+
+	return cache
+}
+
+// Stale - 允许过期后继续返回旧值的时长（0 表示关闭）
+func (this *MemoryClass) Stale(second any) CacheAPI {
+
+	cache := this.clone()
+	cache.Body.Stale = CacheInst.duration(second)
+
+	return cache
+}
+
+// Negative - 空结果的缓存时长（0 表示关闭）
+func (this *MemoryClass) Negative(second any) CacheAPI {
+
+	cache := this.clone()
+	cache.Body.Negative = CacheInst.duration(second)
+
+	return cache
+}
+
+// body - 当前链式参数
+func (this *MemoryClass) body() CacheBody { return this.Body }
+
+// Has - 判断缓存是否存在
+func (this *MemoryClass) Has(key string) (ok bool) {
+	ok, _ = this.HasCtx(context.Background(), key)
+	return ok
+}
+
+// Get - 获取缓存
+func (this *MemoryClass) Get(key string) (value any) {
+	value, _ = this.GetCtx(context.Background(), key)
+	return value
+}
+
+// Set - 设置缓存
+func (this *MemoryClass) Set(key string, value any) (ok bool) {
+	return this.SetCtx(context.Background(), key, value) == nil
+}
+
+// Delete - 删除缓存
+func (this *MemoryClass) Delete(key ...string) (ok bool) {
+	return this.DeleteCtx(context.Background(), key...) == nil
+}
+
+// Clear - 清空缓存
+func (this *MemoryClass) Clear() (ok bool) {
+	return this.ClearCtx(context.Background()) == nil
+}
+
+// HasCtx - 判断缓存是否存在（支持上下文）
+func (this *MemoryClass) HasCtx(ctx context.Context, key string) (ok bool, err error) {
+
+	if utils.Is.Empty(key) { return false, ErrCacheKeyEmpty }
+	if err = ctx.Err(); err != nil { return false, err }
+
+	return this.Store.get(this.Name(key), false) != nil, nil
+}
+
+// GetCtx - 获取缓存（支持上下文）
+func (this *MemoryClass) GetCtx(ctx context.Context, key string) (value any, err error) {
+
+	data, err := this.GetRaw(ctx, key)
+	if err != nil { return nil, err }
+
+	if err = CacheInst.decode(this.Codec(), key, data, &value); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCacheDecode, err)
+	}
+
+	return value, nil
+}
+
+// SetCtx - 设置缓存（支持上下文）
+func (this *MemoryClass) SetCtx(ctx context.Context, key string, value any) (err error) {
+
+	data, err := CacheInst.encode(this.Codec(), key, value)
+	if err != nil { return fmt.Errorf("编码缓存失败: %w", err) }
+
+	return this.SetRaw(ctx, key, data)
+}
+
+// DeleteCtx - 删除缓存（支持上下文）
+func (this *MemoryClass) DeleteCtx(ctx context.Context, key ...string) (err error) {
+
+	cache := this.clone()
+	if cache == nil { return fmt.Errorf("内存缓存未初始化") }
+	if err = ctx.Err(); err != nil { return err }
+
+	if cloned, ok := cache.Keys(key).(*MemoryClass); ok {
+		cache = cloned
+	}
+
+	start := time.Now()
+	cache.Store.delete(cache.Body.Keys...)
+	// 根据标签删除缓存
+	cache.DelTags()
+	cache.counter.remove(key, cache.Body.Tags, start, nil)
+	// 重置配置
+	cache.Reset()
+
+	return nil
+}
+
+// ClearCtx - 清空缓存（支持上下文）
+func (this *MemoryClass) ClearCtx(ctx context.Context) (err error) {
+
+	if err = ctx.Err(); err != nil { return err }
+
+	this.Store.clear()
+	return nil
+}
+
+// GetRaw - 获取编码后的原始缓存值
+func (this *MemoryClass) GetRaw(ctx context.Context, key string) (data []byte, err error) {
+
+	if utils.Is.Empty(key) { return nil, ErrCacheKeyEmpty }
+	if err = ctx.Err(); err != nil { return nil, err }
+
+	start := time.Now()
+	item  := this.Store.get(this.Name(key), true)
+	if item == nil {
+		this.counter.read(key, start, ErrCacheMiss)
+		return nil, ErrCacheMiss
+	}
+
+	this.counter.read(key, start, nil)
+	// 返回副本，调用方修改时不会影响缓存中的值
+	return bytes.Clone(item.value), nil
+}
+
+// SetRaw - 写入编码后的原始缓存值
+func (this *MemoryClass) SetRaw(ctx context.Context, key string, data []byte) (err error) {
+
+	cache := this.clone()
+	if cache == nil { return fmt.Errorf("内存缓存未初始化") }
+	if utils.Is.Empty(key) { return ErrCacheKeyEmpty }
+	if err = ctx.Err(); err != nil { return err }
+
+	item := &memoryEntry{
+		name:  cache.Name(key),
+		key:   key,
+		value: bytes.Clone(data),
+		tags:  cache.Body.Tags,
+	}
+	if cache.Body.Expired > 0 {
+		item.expired = time.Now().Add(cache.Body.Expired)
+	}
+
+	start := time.Now()
+	cache.Store.set(item)
+	cache.counter.write(key, start, nil)
+	// 重置配置
+	cache.Reset()
+
+	return nil
+}
+
+// TTL - 获取缓存剩余存活时间（-1 表示永不过期）
+func (this *MemoryClass) TTL(key string) (ttl time.Duration, err error) {
+
+	if utils.Is.Empty(key) { return 0, ErrCacheKeyEmpty }
+
+	item := this.Store.get(this.Name(key), false)
+	if item == nil { return 0, ErrCacheMiss }
+
+	if item.expired.IsZero() { return -1, nil }
+
+	return time.Until(item.expired), nil
+}
+
+// Touch - 重新设置缓存的过期时间
+func (this *MemoryClass) Touch(key string, ttl any) (ok bool) {
+
+	if utils.Is.Empty(key) { return false }
+
+	cache, _ := this.Expired(ttl).(*MemoryClass)
+
+	var expired time.Time
+	if cache.Body.Expired > 0 {
+		expired = time.Now().Add(cache.Body.Expired)
+	}
+
+	return this.Store.touch(this.Name(key), expired)
+}
+
+// GetMany - 批量获取缓存
+func (this *MemoryClass) GetMany(keys []string) (values map[string]any) {
+
+	items := make(map[string][]byte, len(keys))
+
+	for _, key := range keys {
+		if data, err := this.GetRaw(context.Background(), key); err == nil {
+			items[key] = data
+		}
+	}
+
+	return CacheInst.decodeMany(this.Codec(), items)
+}
+
+// SetMany - 批量设置缓存
+func (this *MemoryClass) SetMany(values map[string]any) (ok bool) {
+
+	items, err := CacheInst.encodeMany(this.Codec(), values)
+	if err != nil { return false }
+
+	for key, data := range items {
+		if err = this.SetRaw(context.Background(), key, data); err != nil { return false }
+	}
+
+	return true
+}
+
+// DeleteByPattern - 按通配符删除缓存
+func (this *MemoryClass) DeleteByPattern(pattern string) (count int, err error) {
+
+	keys := this.Store.scan(pattern)
+	if len(keys) == 0 { return 0, nil }
+
+	if err = this.clone().DeleteCtx(context.Background(), CacheInst.markers(keys)...); err != nil { return 0, err }
+
+	return len(keys), nil
+}
+
+// Export - 以 JSON Lines 导出所有未过期的缓存
+func (this *MemoryClass) Export(w io.Writer) (count int, err error) {
+	return CacheInst.export(w, this.Codec(), func(yield func(item cacheRecord) error) error {
+		for _, item := range this.Store.records() {
+			if err := yield(item); err != nil { return err }
+		}
+		return nil
+	})
+}
+
+// Import - 导入 Export 导出的缓存
+func (this *MemoryClass) Import(r io.Reader) (count int, err error) {
+	return CacheInst.load(this, r)
+}
+
+// Incr - 原子自增
+func (this *MemoryClass) Incr(key string, delta int64) (value int64, err error) {
+	return this.IncrWithTTL(key, delta, nil)
+}
+
+// Decr - 原子自减
+func (this *MemoryClass) Decr(key string, delta int64) (value int64, err error) {
+	return this.IncrWithTTL(key, -delta, nil)
+}
+
+// IncrWithTTL - 原子自增，仅在首次创建时设置过期时间
+func (this *MemoryClass) IncrWithTTL(key string, delta int64, ttl any) (value int64, err error) {
+
+	cache := this.clone()
+	if cache == nil { return 0, fmt.Errorf("内存缓存未初始化") }
+	if utils.Is.Empty(key) { return 0, ErrCacheKeyEmpty }
+
+	if ttl != nil { cache, _ = cache.Expired(ttl).(*MemoryClass) }
+	// 重置配置
+	defer cache.Reset()
+
+	item := &memoryEntry{
+		name: cache.Name(key),
+		key:  key,
+		tags: cache.Body.Tags,
+	}
+	if cache.Body.Expired > 0 {
+		item.expired = time.Now().Add(cache.Body.Expired)
+	}
+
+	return cache.Store.incr(item, delta)
+}
+
+// Scan - 按通配符列出缓存的原始 key
+func (this *MemoryClass) Scan(pattern string) (keys []string, err error) {
+	return this.Store.scan(pattern), nil
+}
+
+// Stats - 缓存统计
+func (this *MemoryClass) Stats() (stats CacheStats) {
+
+	stats = this.counter.stats("memory")
+	stats.Keys, stats.Size = this.Store.stats()
+
+	return stats
+}
+
+// Codec - 当前使用的编解码器
+func (this *MemoryClass) Codec() CacheCodec {
+	if this.codec == nil { return CacheInst.codec("json") }
+	return this.codec
+}
+
+// Remember - 读取缓存，未命中时回源并写入
+func (this *MemoryClass) Remember(key string, ttl any, fn func() (any, error)) (value any, err error) {
+	return CacheRemember[any](this, key, ttl, fn)
+}
+
+// flight - 进程内请求合并组
+func (this *MemoryClass) flight() *singleflight.Group {
+	if this.group == nil { this.group = &singleflight.Group{} }
+	return this.group
+}
+
+// lock - 内存缓存仅在进程内合并请求，不需要额外的锁
+func (this *MemoryClass) lock(ctx context.Context, key string) (unlock func(), ok bool, err error) {
+	return func() {}, true, nil
+}
+
+// lockWait - 内存缓存不需要等待
+func (this *MemoryClass) lockWait() time.Duration { return 0 }
+
+// Name - 缓存名称规则 - 辅助方法
+func (this *MemoryClass) Name(key string) string {
+	return fmt.Sprintf("%s-%s", this.Body.Prefix, utils.Hash.Sum32(key))
+}
+
+// Reset - 重置配置 - 辅助方法
+func (this *MemoryClass) Reset() {
+	this.Body.Keys    = []string{}
+	this.Body.Tags    = []string{}
+	this.Body.Expired = time.Duration(this.Config.Expired) * time.Second
+}
+
+// DelTags - 删除标签下的所有缓存 - 辅助方法
+func (this *MemoryClass) DelTags() {
+	this.Store.delTags(this.Body.Tags...)
+}
+
+// get - 读取条目，touch=true 时把条目移到表头
+func (this *MemoryStore) get(name string, touch bool) (item *memoryEntry) {
+
+	defer this.notify()
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	element, ok := this.items[name]
+	if !ok { return nil }
+
+	item = element.Value.(*memoryEntry)
+	if item.expire(time.Now()) {
+		this.drop(element, CacheEventExpire)
+		return nil
+	}
+
+	if touch { this.list.MoveToFront(element) }
+
+	return item
+}
+
+// set - 写入条目并按容量淘汰最久未使用的条目
+func (this *MemoryStore) set(item *memoryEntry) {
+
+	defer this.notify()
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	// 覆盖写入时保留原有标签，与 Redis / 文件缓存的标签语义一致
+	if element, ok := this.items[item.name]; ok {
+		tags := append(element.Value.(*memoryEntry).tags, item.tags...)
+		item.tags = cast.ToStringSlice(utils.ArrayUnique(tags))
+		this.remove(element)
+	}
+
+	this.insert(item)
+}
+
+// insert - 插入条目到表头并按容量淘汰（调用方需持有锁）
+func (this *MemoryStore) insert(item *memoryEntry) {
+
+	this.items[item.name] = this.list.PushFront(item)
+	this.bytes += item.size()
+
+	for _, tag := range item.tags {
+		if _, ok := this.tags[tag]; !ok {
+			this.tags[tag] = make(map[string]struct{})
+		}
+		this.tags[tag][item.name] = struct{}{}
+	}
+
+	// 超出容量时先清理已过期的条目，仍然超出再淘汰最久未使用的条目
+	if this.overflow() { this.sweep(time.Now()) }
+
+	for this.list.Len() > 1 && this.overflow() {
+		this.drop(this.list.Back(), CacheEventEvict)
+	}
+}
+
+// sweep - 清理所有已过期的条目（调用方需持有锁）
+func (this *MemoryStore) sweep(now time.Time) {
+
+	for element := this.list.Front(); element != nil; {
+		next := element.Next()
+		if element.Value.(*memoryEntry).expire(now) { this.drop(element, CacheEventExpire) }
+		element = next
+	}
+}
+
+// touch - 更新条目的过期时间
+func (this *MemoryStore) touch(name string, expired time.Time) (ok bool) {
+
+	defer this.notify()
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	element, ok := this.items[name]
+	if !ok { return false }
+
+	item := element.Value.(*memoryEntry)
+	if item.expire(time.Now()) {
+		this.drop(element, CacheEventExpire)
+		return false
+	}
+
+	item.expired = expired
+	return true
+}
+
+// incr - 自增条目的值，条目不存在时按 item 创建
+func (this *MemoryStore) incr(item *memoryEntry, delta int64) (value int64, err error) {
+
+	defer this.notify()
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	element, ok := this.items[item.name]
+	if ok && element.Value.(*memoryEntry).expire(time.Now()) {
+		this.drop(element, CacheEventExpire)
+		ok = false
+	}
+
+	if ok {
+		current := element.Value.(*memoryEntry)
+		if value, err = strconv.ParseInt(strings.TrimSpace(string(current.value)), 10, 64); err != nil {
+			return 0, fmt.Errorf("缓存值不是整数: %w", err)
+		}
+		// 沿用原有的过期时间和标签
+		item.expired = current.expired
+		item.tags    = current.tags
+	}
+
+	value += delta
+	item.value = []byte(strconv.FormatInt(value, 10))
+
+	if ok { this.remove(element) }
+	this.insert(item)
+
+	return value, nil
+}
+
+// records - 未过期条目的快照（按最近使用排序）
+func (this *MemoryStore) records() (items []cacheRecord) {
+
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	now := time.Now()
+	for element := this.list.Front(); element != nil; element = element.Next() {
+
+		entry := element.Value.(*memoryEntry)
+		if entry.expire(now) { continue }
+
+		item := cacheRecord{ key: entry.key, data: entry.value }
+		if !entry.expired.IsZero() { item.ttl = max(entry.expired.Sub(now), time.Millisecond) }
+		for _, tag := range entry.tags {
+			item.tags = append(item.tags, CacheInst.exportTag(tag))
+		}
+
+		items = append(items, item)
+	}
+
+	return items
+}
+
+// scan - 列出匹配通配符且未过期的原始 key
+func (this *MemoryStore) scan(pattern string) (keys []string) {
+
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	now := time.Now()
+	for element := this.list.Front(); element != nil; element = element.Next() {
+		item := element.Value.(*memoryEntry)
+		if item.expire(now) { continue }
+		if CacheInst.match(pattern, item.key) { keys = append(keys, item.key) }
+	}
+
+	return keys
+}
+
+// stats - 当前条目数和占用字节数（统计前清理已过期的条目）
+func (this *MemoryStore) stats() (keys, size int64) {
+
+	defer this.notify()
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	this.sweep(time.Now())
+
+	return int64(this.list.Len()), this.bytes
+}
+
+// overflow - 是否超出容量
+func (this *MemoryStore) overflow() bool {
+	if this.maxEntries > 0 && this.list.Len() > this.maxEntries { return true }
+	if this.maxBytes > 0 && this.bytes > this.maxBytes { return true }
+	return false
+}
+
+// delete - 按缓存名称删除
+func (this *MemoryStore) delete(names ...string) {
+
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	for _, name := range names {
+		if element, ok := this.items[name]; ok {
+			this.remove(element)
+		}
+	}
+}
+
+// delTags - 删除标签及其下所有缓存
+func (this *MemoryStore) delTags(tags ...string) {
+
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	for _, tag := range tags {
+		for name := range this.tags[tag] {
+			if element, ok := this.items[name]; ok {
+				this.remove(element)
+			}
+		}
+		delete(this.tags, tag)
+	}
+}
+
+// clear - 清空存储
+func (this *MemoryStore) clear() {
+
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	this.list.Init()
+	this.items = make(map[string]*list.Element)
+	this.tags  = make(map[string]map[string]struct{})
+	this.bytes = 0
+}
+
+// remove - 移除节点（调用方需持有锁）
+func (this *MemoryStore) remove(element *list.Element) {
+
+	item := this.list.Remove(element).(*memoryEntry)
+	delete(this.items, item.name)
+	this.bytes -= item.size()
+
+	for _, tag := range item.tags {
+		if members, ok := this.tags[tag]; ok {
+			delete(members, item.name)
+			if len(members) == 0 { delete(this.tags, tag) }
+		}
+	}
+}
+
+// drop - 淘汰或清理过期节点，并记录待上报的事件（调用方需持有锁）
+func (this *MemoryStore) drop(element *list.Element, kind CacheEventType) {
+
+	item := element.Value.(*memoryEntry)
+	this.remove(element)
+
+	if this.counter != nil && cacheObservers.Count.Load() > 0 {
+		this.events = append(this.events, memoryEvent{kind: kind, key: item.key})
+	}
+}
+
+// notify - 上报待处理的事件（调用方不能持有锁）
+func (this *MemoryStore) notify() {
+
+	if cacheObservers.Count.Load() == 0 { return }
+
+	this.mutex.Lock()
+	events := this.events
+	this.events = nil
+	this.mutex.Unlock()
+
+	for _, item := range events {
+		this.counter.emit(item.kind, string(item.kind), item.key, nil, time.Time{}, nil)
+	}
+}
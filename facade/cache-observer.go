@@ -0,0 +1,354 @@
+package facade
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CacheEventType - 缓存事件类型
+type CacheEventType string
+
+const (
+	// CacheEventHit - 读取命中
+	CacheEventHit    CacheEventType = "hit"
+	// CacheEventMiss - 读取未命中
+	CacheEventMiss   CacheEventType = "miss"
+	// CacheEventSet - 写入
+	CacheEventSet    CacheEventType = "set"
+	// CacheEventDelete - 删除（按 key 或标签）
+	CacheEventDelete CacheEventType = "delete"
+	// CacheEventEvict - 因容量不足被淘汰
+	CacheEventEvict  CacheEventType = "evict"
+	// CacheEventExpire - 过期后被清理
+	CacheEventExpire CacheEventType = "expire"
+	// CacheEventError - 后端错误
+	CacheEventError  CacheEventType = "error"
+)
+
+// CacheEvent - 缓存事件
+type CacheEvent struct {
+	// 事件类型
+	Type     CacheEventType
+	// 操作：get / set / delete / evict / expire
+	Op       string
+	// 原始 key（按标签删除时为空）
+	Key      string
+	// 标签（按标签删除时）
+	Tags     []string
+	// 引擎：redis / file / memory / tiered
+	Engine   string
+	// 耗时
+	Duration time.Duration
+	// 错误（仅 CacheEventError）
+	Err      error
+}
+
+// CacheObserver - 缓存事件观察者
+/**
+ * Observe 在缓存调用方的协程中同步执行，实现需要足够快且并发安全。
+ */
+type CacheObserver interface {
+	Observe(event CacheEvent)
+}
+
+// CacheObserverFunc - 函数形式的观察者
+type CacheObserverFunc func(event CacheEvent)
+
+// Observe - 处理事件
+func (this CacheObserverFunc) Observe(event CacheEvent) { this(event) }
+
+// cacheObserverEntry - 一次注册（同一个观察者可以注册多次）
+type cacheObserverEntry struct {
+	observer CacheObserver
+}
+
+// cacheObservers - 已注册的观察者（对所有引擎、所有实例生效）
+var cacheObservers = struct {
+	Mutex sync.RWMutex
+	Items []*cacheObserverEntry
+	Count atomic.Int32
+}{}
+
+// Observe - 注册缓存事件观察者
+/**
+ * @param observer 观察者
+ * @return func() 取消注册
+ * @example：
+ * collector := facade.NewCacheCollector()
+ * cancel := facade.CacheInst.Observe(collector)
+ * http.Handle("/metrics/cache", collector)
+ */
+func (this *CacheClass) Observe(observer CacheObserver) (cancel func()) {
+
+	if observer == nil { return func() {} }
+
+	entry := &cacheObserverEntry{observer: observer}
+
+	cacheObservers.Mutex.Lock()
+	cacheObservers.Items = append(cacheObservers.Items, entry)
+	cacheObservers.Count.Store(int32(len(cacheObservers.Items)))
+	cacheObservers.Mutex.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			cacheObservers.Mutex.Lock()
+			defer cacheObservers.Mutex.Unlock()
+			for i, item := range cacheObservers.Items {
+				if item != entry { continue }
+				// 复制一份新切片，避免影响正在分发的事件
+				cacheObservers.Items = append(cacheObservers.Items[:i:i], cacheObservers.Items[i+1:]...)
+				break
+			}
+			cacheObservers.Count.Store(int32(len(cacheObservers.Items)))
+		})
+	}
+}
+
+// notify - 分发事件
+func (this *CacheClass) notify(event CacheEvent) {
+
+	cacheObservers.Mutex.RLock()
+	items := cacheObservers.Items
+	cacheObservers.Mutex.RUnlock()
+
+	for _, item := range items {
+		item.observer.Observe(event)
+	}
+}
+
+// cacheCounter - 命中计数与事件上报（同一实例的所有克隆共享）
+type cacheCounter struct {
+	hits   atomic.Int64
+	misses atomic.Int64
+	// 引擎名称
+	engine string
+	// 作为二级缓存的内部层时不上报读写删事件，由二级缓存统一上报（淘汰、过期仍按本层上报）
+	silent bool
+}
+
+// record - 按读取结果记录命中或未命中
+func (this *cacheCounter) record(err error) {
+
+	if this == nil { return }
+
+	if err == nil {
+		this.hits.Add(1)
+	} else if errors.Is(err, ErrCacheMiss) {
+		this.misses.Add(1)
+	}
+}
+
+// read - 记录一次读取并上报 hit / miss / error
+func (this *cacheCounter) read(key string, start time.Time, err error) {
+
+	this.record(err)
+	if this == nil || this.silent { return }
+
+	switch {
+	case err == nil:
+		this.emit(CacheEventHit, "get", key, nil, start, nil)
+	case errors.Is(err, ErrCacheMiss):
+		this.emit(CacheEventMiss, "get", key, nil, start, nil)
+	default:
+		this.emit(CacheEventError, "get", key, nil, start, err)
+	}
+}
+
+// write - 上报一次写入
+func (this *cacheCounter) write(key string, start time.Time, err error) {
+
+	if this == nil || this.silent { return }
+
+	if err != nil {
+		this.emit(CacheEventError, "set", key, nil, start, err)
+		return
+	}
+
+	this.emit(CacheEventSet, "set", key, nil, start, nil)
+}
+
+// remove - 上报一次删除（按 key 和标签）
+func (this *cacheCounter) remove(keys []string, tags []string, start time.Time, err error) {
+
+	if this == nil || this.silent || cacheObservers.Count.Load() == 0 { return }
+
+	// 标签统一上报为用户传入的形式
+	if len(tags) > 0 {
+		items := make([]string, len(tags))
+		for i, tag := range tags { items[i] = CacheInst.exportTag(tag) }
+		tags = items
+	}
+
+	if err != nil {
+		this.emit(CacheEventError, "delete", strings.Join(keys, ","), tags, start, err)
+		return
+	}
+
+	for _, key := range keys {
+		this.emit(CacheEventDelete, "delete", key, nil, start, nil)
+	}
+	if len(tags) > 0 {
+		this.emit(CacheEventDelete, "delete", "", tags, start, nil)
+	}
+}
+
+// emit - 上报事件（没有观察者时不做任何事）
+func (this *cacheCounter) emit(kind CacheEventType, op, key string, tags []string, start time.Time, err error) {
+
+	if this == nil || cacheObservers.Count.Load() == 0 { return }
+
+	event := CacheEvent{
+		Type:   kind,
+		Op:     op,
+		Key:    key,
+		Tags:   tags,
+		Engine: this.engine,
+		Err:    err,
+	}
+	if !start.IsZero() { event.Duration = time.Since(start) }
+
+	CacheInst.notify(event)
+}
+
+// stats - 生成统计结果
+func (this *cacheCounter) stats(engine string) CacheStats {
+
+	stats := CacheStats{Engine: engine}
+	if this == nil { return stats }
+
+	stats.Hits   = this.hits.Load()
+	stats.Misses = this.misses.Load()
+	return stats
+}
+
+// CacheCollector - 内置的缓存指标收集器（Prometheus 文本格式）
+/**
+ * 输出的指标：
+ * inis_cache_events_total{engine,event}          事件计数
+ * inis_cache_duration_seconds{engine,op}         耗时直方图（get / set / delete）
+ */
+type CacheCollector struct {
+	mutex   sync.Mutex
+	// 直方图的桶（秒）
+	buckets []float64
+	// engine|event -> 次数
+	events  map[[2]string]int64
+	// engine|op -> 直方图
+	latency map[[2]string]*cacheHistogram
+}
+
+// cacheHistogram - 耗时直方图
+type cacheHistogram struct {
+	counts []int64
+	sum    float64
+	count  int64
+}
+
+// NewCacheCollector - 创建缓存指标收集器
+/**
+ * @param buckets 直方图的桶（秒），不传时使用默认值
+ * @return *CacheCollector
+ */
+func NewCacheCollector(buckets ...float64) *CacheCollector {
+
+	if len(buckets) == 0 {
+		buckets = []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1}
+	}
+
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+
+	return &CacheCollector{
+		buckets: buckets,
+		events:  make(map[[2]string]int64),
+		latency: make(map[[2]string]*cacheHistogram),
+	}
+}
+
+// Observe - 记录事件
+func (this *CacheCollector) Observe(event CacheEvent) {
+
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	this.events[[2]string{event.Engine, string(event.Type)}]++
+
+	// 只统计调用方可感知的操作耗时
+	if event.Duration <= 0 || (event.Op != "get" && event.Op != "set" && event.Op != "delete") { return }
+
+	index := [2]string{event.Engine, event.Op}
+	item, ok := this.latency[index]
+	if !ok {
+		item = &cacheHistogram{counts: make([]int64, len(this.buckets))}
+		this.latency[index] = item
+	}
+
+	seconds := event.Duration.Seconds()
+	for i, bound := range this.buckets {
+		if seconds <= bound { item.counts[i]++ }
+	}
+	item.sum   += seconds
+	item.count++
+}
+
+// WriteTo - 以 Prometheus 文本格式输出
+func (this *CacheCollector) WriteTo(w io.Writer) (total int64, err error) {
+
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	var builder strings.Builder
+
+	builder.WriteString("# HELP inis_cache_events_total Total number of cache events.\n")
+	builder.WriteString("# TYPE inis_cache_events_total counter\n")
+	for _, index := range this.sorted(this.events) {
+		fmt.Fprintf(&builder, "inis_cache_events_total{engine=%q,event=%q} %d\n", index[0], index[1], this.events[index])
+	}
+
+	builder.WriteString("# HELP inis_cache_duration_seconds Latency of cache operations.\n")
+	builder.WriteString("# TYPE inis_cache_duration_seconds histogram\n")
+	for _, index := range this.sorted(this.latency) {
+		item := this.latency[index]
+		for i, bound := range this.buckets {
+			fmt.Fprintf(&builder, "inis_cache_duration_seconds_bucket{engine=%q,op=%q,le=%q} %d\n", index[0], index[1], fmt.Sprint(bound), item.counts[i])
+		}
+		fmt.Fprintf(&builder, "inis_cache_duration_seconds_bucket{engine=%q,op=%q,le=\"+Inf\"} %d\n", index[0], index[1], item.count)
+		fmt.Fprintf(&builder, "inis_cache_duration_seconds_sum{engine=%q,op=%q} %g\n", index[0], index[1], item.sum)
+		fmt.Fprintf(&builder, "inis_cache_duration_seconds_count{engine=%q,op=%q} %d\n", index[0], index[1], item.count)
+	}
+
+	written, err := io.WriteString(w, builder.String())
+	return int64(written), err
+}
+
+// ServeHTTP - 作为 HTTP 处理器输出指标
+func (this *CacheCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = this.WriteTo(w)
+}
+
+// sorted - 按标签排序，保证输出稳定
+func (this *CacheCollector) sorted(items any) (keys [][2]string) {
+
+	switch values := items.(type) {
+	case map[[2]string]int64:
+		for key := range values { keys = append(keys, key) }
+	case map[[2]string]*cacheHistogram:
+		for key := range values { keys = append(keys, key) }
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] { return keys[i][0] < keys[j][0] }
+		return keys[i][1] < keys[j][1]
+	})
+
+	return keys
+}
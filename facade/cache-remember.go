@@ -0,0 +1,214 @@
+package facade
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// cacheRememberer - Remember 所需的引擎能力
+type cacheRememberer interface {
+	// flight - 进程内的请求合并组（同一实例的所有克隆共享）
+	flight() *singleflight.Group
+	// lock - 获取跨进程的回源锁，ok=false 表示锁被其他实例持有
+	lock(ctx context.Context, key string) (unlock func(), ok bool, err error)
+	// lockWait - 锁被其他实例持有时的最长等待时间
+	lockWait() time.Duration
+	// body - 当前链式参数（过期时间、Stale、Negative）
+	body() CacheBody
+}
+
+// cacheFreshSuffix - 新鲜度标记的 key 后缀（标记存在表示缓存值仍在有效期内）
+const cacheFreshSuffix = "#fresh"
+
+// cacheNegativeSuffix - 空结果标记的 key 后缀
+const cacheNegativeSuffix = "#negative"
+
+// marker - 是否为 Remember 写入的内部标记（不出现在 Scan / Export / DeleteByPattern 的结果中）
+func (this *CacheClass) marker(key string) bool {
+	return strings.HasSuffix(key, cacheFreshSuffix) || strings.HasSuffix(key, cacheNegativeSuffix)
+}
+
+// markers - 原始 key 及其内部标记，按通配符删除时一并清理
+func (this *CacheClass) markers(keys []string) (items []string) {
+	for _, key := range keys {
+		items = append(items, key, key + cacheFreshSuffix, key + cacheNegativeSuffix)
+	}
+	return items
+}
+
+// CacheRemember - 按类型读取缓存，未命中时调用 fn 计算并写入
+/**
+ * 同一进程内同一个 key 的并发未命中只会调用一次 fn；
+ * 当引擎支持分布式锁（如 Redis 开启 Lock）时，集群内也只会有一个实例回源。
+ * 链式设置 Stale 时，过期后的旧值会继续返回并在后台刷新；
+ * 链式设置 Negative 时，fn 返回 ErrCacheNotFound 的结果会被缓存。
+ * 缓存后端异常时同样经过请求合并回源；缓存值无法解码时按未命中处理并覆盖写入。
+ * @param cache 缓存实例
+ * @param key 缓存的key
+ * @param ttl 过期时间（为 nil 时沿用链式调用上的过期时间）
+ * @param fn 回源函数
+ * @return T 缓存值, error
+ * @example：
+ * user, err := facade.CacheRemember(facade.Cache, "user:1", 5*time.Minute, func() (User, error) { return repo.Find(1) })
+ */
+func CacheRemember[T any](cache CacheAPI, key string, ttl any, fn func() (T, error)) (value T, err error) {
+	return CacheRememberCtx[T](context.Background(), cache, key, ttl, fn)
+}
+
+// CacheRememberCtx - 按类型读取缓存，未命中时调用 fn 计算并写入（支持上下文）
+func CacheRememberCtx[T any](ctx context.Context, cache CacheAPI, key string, ttl any, fn func() (T, error)) (value T, err error) {
+
+	if cache == nil { return value, fmt.Errorf("缓存未初始化") }
+	if fn == nil { return value, fmt.Errorf("回源函数不能为空") }
+
+	if ttl != nil { cache = cache.Expired(ttl) }
+
+	var body CacheBody
+	item, ok := cache.(cacheRememberer)
+	if ok { body = item.body() }
+
+	// 快速路径：直接命中
+	value, hit, err := cacheRead[T](ctx, cache, key)
+	if hit {
+		// 已超过新鲜期：先返回旧值，再在后台刷新
+		if body.Stale > 0 && item != nil {
+			if fresh, err := cache.HasCtx(ctx, key + cacheFreshSuffix); err == nil && !fresh {
+				cacheRefresh[T](ctx, cache, item, key, fn)
+			}
+		}
+		return value, nil
+	}
+	// 空结果仍在有效期内（后端异常时跳过，直接进入下面的合并回源）
+	if err == nil && body.Negative > 0 {
+		if negative, _ := cache.HasCtx(ctx, key + cacheNegativeSuffix); negative {
+			return value, ErrCacheNotFound
+		}
+	}
+
+	load := func() (any, error) { return cacheLoad[T](ctx, cache, key, fn) }
+
+	if !ok { return cacheLoad[T](ctx, cache, key, fn) }
+
+	// 未命中与后端异常都经过同一个合并组回源，缓存故障时也不会击穿到 fn
+	// 按值类型区分合并组，避免不同类型的调用方共享结果
+	result, err, _ := item.flight().Do(fmt.Sprintf("%s|%T", key, value), load)
+	if err != nil { return value, err }
+
+	// fn 返回 nil 且 T 为接口类型时 result 为 nil，直接断言会 panic
+	value, _ = result.(T)
+	return value, nil
+}
+
+// cacheRead - Remember 使用的读取：解码失败按未命中处理，回源后覆盖写入
+func cacheRead[T any](ctx context.Context, cache CacheAPI, key string) (value T, ok bool, err error) {
+
+	value, ok, err = CacheGetCtx[T](ctx, cache, key)
+	if errors.Is(err, ErrCacheDecode) { return value, false, nil }
+
+	return value, ok, err
+}
+
+// cacheLoad - 回源并写入缓存（在 singleflight 内执行）
+func cacheLoad[T any](ctx context.Context, cache CacheAPI, key string, fn func() (T, error)) (value T, err error) {
+
+	// 二次检查：可能刚被上一轮请求写入
+	if value, ok, _ := cacheRead[T](ctx, cache, key); ok { return value, nil }
+
+	if item, ok := cache.(cacheRememberer); ok {
+
+		unlock, acquired, err := item.lock(ctx, key)
+
+		if err == nil && acquired {
+			defer unlock()
+			// 拿到锁后再检查一次，其他实例可能刚刚写完
+			if value, ok, _ := cacheRead[T](ctx, cache, key); ok { return value, nil }
+		}
+
+		// 锁被其他实例持有：等待对方写入缓存，超时后自行回源
+		if err == nil && !acquired {
+			if value, ok := cacheWait[T](ctx, cache, key, item.lockWait()); ok { return value, nil }
+		}
+	}
+
+	value, err = fn()
+	cacheStore[T](ctx, cache, key, value, err)
+
+	return value, err
+}
+
+// cacheStore - 按链式参数写入回源结果（含新鲜度标记和空结果标记）
+func cacheStore[T any](ctx context.Context, cache CacheAPI, key string, value T, err error) {
+
+	var body CacheBody
+	if item, ok := cache.(cacheRememberer); ok { body = item.body() }
+
+	if err != nil {
+		if errors.Is(err, ErrCacheNotFound) && body.Negative > 0 {
+			_ = cache.Expired(body.Negative).SetCtx(ctx, key + cacheNegativeSuffix, true)
+		}
+		return
+	}
+
+	if body.Stale <= 0 {
+		_ = CacheSetCtx[T](ctx, cache, key, value)
+		return
+	}
+
+	// 缓存值多保留 Stale 时长，新鲜度标记按原过期时间失效
+	if CacheSetCtx[T](ctx, cache.Expired(body.Expired + body.Stale), key, value) == nil {
+		_ = cache.SetCtx(ctx, key + cacheFreshSuffix, true)
+	}
+}
+
+// cacheRefresh - 在后台刷新过期的缓存值（同一 key 同时只会有一个刷新任务）
+func cacheRefresh[T any](ctx context.Context, cache CacheAPI, item cacheRememberer, key string, fn func() (T, error)) {
+
+	var zero T
+	// 脱离调用方的取消信号，请求结束后刷新仍可完成
+	ctx = context.WithoutCancel(ctx)
+
+	item.flight().DoChan(fmt.Sprintf("refresh|%s|%T", key, zero), func() (any, error) {
+
+		unlock, acquired, err := item.lock(ctx, key)
+		// 其他实例正在刷新
+		if err != nil || !acquired { return nil, err }
+		defer unlock()
+
+		// 拿到锁后确认是否已被其他实例刷新
+		if fresh, err := cache.HasCtx(ctx, key + cacheFreshSuffix); err != nil || fresh { return nil, err }
+
+		value, err := fn()
+		// 刷新失败时保留旧值，等待下一次读取重试
+		if err == nil { cacheStore[T](ctx, cache, key, value, nil) }
+
+		return nil, err
+	})
+}
+
+// cacheWait - 轮询等待其他实例写入缓存
+func cacheWait[T any](ctx context.Context, cache CacheAPI, key string, wait time.Duration) (value T, ok bool) {
+
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return value, false
+		case <-timer.C:
+			return value, false
+		case <-ticker.C:
+			value, ok, err := cacheRead[T](ctx, cache, key)
+			if ok { return value, true }
+			if err != nil && !errors.Is(err, ErrCacheMiss) { return value, false }
+		}
+	}
+}
@@ -0,0 +1,667 @@
+package facade
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/inis-io/aide/dto"
+	"github.com/inis-io/aide/utils"
+	"github.com/redis/go-redis/v9"
+	"github.com/spf13/cast"
+	"golang.org/x/sync/singleflight"
+)
+
+// ============================ 二级缓存 ============================
+
+// TieredClass - 二级缓存实现（本地内存 L1 + Redis L2）
+/**
+ * 读取优先命中 L1，未命中再读 L2 并回填 L1（读取期间收到失效通知时放弃回填）；
+ * Set / Delete / 标签删除 / Clear 会通过 Redis 发布订阅通知其他节点丢弃对应的 L1 条目。
+ */
+type TieredClass struct {
+	// 本地内存缓存
+	L1     *MemoryClass
+	// Redis 缓存
+	L2     *RedisClass
+	// 缓存参数
+	Body   CacheBody
+	// 当前配置
+	Config dto.CacheTieredConfig
+	// 失效通知（同一实例的所有克隆共享）
+	bus    *tieredBus
+	// 编解码器
+	codec  CacheCodec
+	// 请求合并组
+	group  *singleflight.Group
+	// 命中计数器
+	counter *cacheCounter
+}
+
+// tieredBus - 跨节点失效通知
+type tieredBus struct {
+	// 当前节点标识，用于忽略自己发出的通知
+	node    string
+	// 通知频道
+	channel string
+	// 订阅对象
+	pubsub  *redis.PubSub
+	// 保证只关闭一次
+	once    sync.Once
+	// 失效版本：按缓存名称分片计数，回填 L1 前后版本不一致时放弃回填
+	versions [tieredVersionShards]atomic.Uint64
+	// 标签删除与清空时整体递增
+	epoch    atomic.Uint64
+}
+
+// tieredVersionShards - 失效版本的分片数
+const tieredVersionShards = 256
+
+// version - 缓存名称当前的失效版本
+func (this *tieredBus) version(name string) uint64 {
+
+	if this == nil { return 0 }
+
+	return this.epoch.Load() + this.versions[this.shard(name)].Load()
+}
+
+// invalidate - 递增缓存名称的失效版本，不传名称时整体递增
+/**
+ * 必须在 L2 修改之后、L1 丢弃之前调用，保证并发回填要么看到新版本，要么被随后的丢弃覆盖。
+ */
+func (this *tieredBus) invalidate(names ...string) {
+
+	if this == nil { return }
+
+	if len(names) == 0 {
+		this.epoch.Add(1)
+		return
+	}
+
+	for _, name := range names {
+		this.versions[this.shard(name)].Add(1)
+	}
+}
+
+// shard - 缓存名称所在的版本分片
+func (this *tieredBus) shard(name string) uint32 {
+
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(name))
+
+	return hash.Sum32() % tieredVersionShards
+}
+
+// tieredMessage - 失效通知内容
+type tieredMessage struct {
+	// 发出通知的节点
+	Node  string   `json:"node"`
+	// 需要丢弃的缓存名称
+	Names []string `json:"names,omitempty"`
+	// 需要丢弃的标签
+	Tags  []string `json:"tags,omitempty"`
+	// 是否清空
+	Clear bool     `json:"clear,omitempty"`
+}
+
+// clone - 克隆二级缓存实例（共享 L1/L2 与订阅，隔离链式上下文）
+func (this *TieredClass) clone() *TieredClass {
+
+	if this == nil { return nil }
+
+	clone := *this
+	clone.Body = CacheInst.cloneBody(this.Body)
+	return &clone
+}
+
+// NewCache - 按配置创建新的缓存实例
+func (this *TieredClass) NewCache(config dto.CacheConfig) CacheAPI {
+	return CacheInst.newWithConfig(config)
+}
+
+// Init - 初始化二级缓存
+func (this *TieredClass) Init(redisConfig dto.CacheRedisConfig, config dto.CacheTieredConfig) {
+
+	this.Config = config
+
+	this.L2 = &RedisClass{codec: this.codec}
+	this.L2.Init(redisConfig)
+
+	this.L1 = &MemoryClass{codec: this.codec}
+	this.L1.Init(dto.CacheMemoryConfig{
+		Expired:    config.Expired,
+		Prefix:     redisConfig.Prefix,
+		MaxEntries: config.MaxEntries,
+		MaxBytes:   config.MaxBytes,
+	})
+
+	this.Body.Prefix  = this.L2.Body.Prefix
+	this.Body.Expired = this.L2.Body.Expired
+	this.group   = &singleflight.Group{}
+	this.counter = &cacheCounter{engine: "tiered"}
+
+	// 读写删事件由二级缓存统一上报，避免同一次操作上报多次
+	this.L1.counter.silent = true
+	this.L2.counter.silent = true
+
+	this.bus = &tieredBus{
+		node:    utils.Gen.UUID(),
+		channel: config.Channel,
+		pubsub:  this.L2.Client.Subscribe(context.Background(), config.Channel),
+	}
+
+	go this.listen(this.bus)
+}
+
+// Close - 停止失效通知订阅
+func (this *TieredClass) Close() {
+
+	if this == nil || this.bus == nil { return }
+
+	this.bus.once.Do(func() { _ = this.bus.pubsub.Close() })
+}
+
+// listen - 处理其他节点发来的失效通知
+func (this *TieredClass) listen(bus *tieredBus) {
+
+	for message := range bus.pubsub.Channel() {
+
+		var item tieredMessage
+		if err := utils.Json.Unmarshal([]byte(message.Payload), &item); err != nil { continue }
+
+		// 忽略自己发出的通知
+		if item.Node == bus.node { continue }
+
+		if item.Clear {
+			bus.invalidate()
+			this.L1.Store.clear()
+			continue
+		}
+
+		bus.invalidate(item.Names...)
+		// 回填的 L1 条目没有标签信息，按标签删除时整体递增版本
+		if len(item.Tags) > 0 { bus.invalidate() }
+
+		this.L1.Store.delete(item.Names...)
+		this.L1.Store.delTags(item.Tags...)
+	}
+}
+
+// publish - 通知其他节点丢弃 L1 条目
+/**
+ * 通知发生在 L2 写入成功之后，发送失败只记录日志，不作为写入失败返回（其他节点的 L1 最迟在 Tiered.Expired 后过期）。
+ */
+func (this *TieredClass) publish(ctx context.Context, item tieredMessage) {
+
+	item.Node = this.bus.node
+	if err := this.L2.Client.Publish(ctx, this.bus.channel, utils.Json.Encode(item)).Err(); err != nil {
+		Log.Error(map[string]any{"channel": this.bus.channel, "error": err.Error()}, "二级缓存失效通知发送失败")
+	}
+}
+
+// layers - 按当前链式上下文生成 L1 / L2 的克隆
+func (this *TieredClass) layers() (l1 *MemoryClass, l2 *RedisClass) {
+
+	l1 = this.L1.clone()
+	l2 = this.L2.clone()
+
+	l1.Body.Keys = CacheInst.cloneStrings(this.Body.Keys)
+	l1.Body.Tags = CacheInst.cloneStrings(this.Body.Tags)
+	l2.Body.Keys = CacheInst.cloneStrings(this.Body.Keys)
+	l2.Body.Tags = CacheInst.cloneStrings(this.Body.Tags)
+
+	l2.Body.Expired = this.Body.Expired
+
+	// L1 的存活时间不超过配置上限，限制通知丢失时的脏读窗口
+	l1.Body.Expired = time.Duration(this.Config.Expired) * time.Second
+	if this.Body.Expired > 0 && this.Body.Expired < l1.Body.Expired {
+		l1.Body.Expired = this.Body.Expired
+	}
+
+	return l1, l2
+}
+
+// Key - 键
+func (this *TieredClass) Key(key ...string) CacheAPI {
+	cache := this.clone()
+	var keys []string
+	// 把多个标签转换为数组
+	for _, value := range key {
+		keys = append(keys, cache.Name(value))
+	}
+	// 合并之前的标签
+	if len(cache.Body.Keys) > 0 {
+		keys = append(keys, cache.Body.Keys...)
+	}
+	// 去重
+	cache.Body.Keys = cast.ToStringSlice(utils.ArrayUnique(keys))
+	return cache
+}
+
+// Keys - 键集
+func (this *TieredClass) Keys(key []string) CacheAPI {
+	return this.Key(key...)
+}
+
+// Tag - 标签
+func (this *TieredClass) Tag(tag ...string) CacheAPI {
+	cache := this.clone()
+	var tags []string
+	// 把多个标签转换为数组
+	for _, value := range tag {
+		tags = append(tags, strings.ToUpper(fmt.Sprintf("tag-%v", value)))
+	}
+	// 合并之前的标签
+	if len(cache.Body.Tags) > 0 {
+		tags = append(tags, cache.Body.Tags...)
+	}
+	// 去重
+	cache.Body.Tags = cast.ToStringSlice(utils.ArrayUnique(tags))
+	return cache
+}
+
+// Tags - 标签
+func (this *TieredClass) Tags(tag []string) CacheAPI {
+	return this.Tag(tag...)
+}
+
+// Expired - 过期时间
+func (this *TieredClass) Expired(second any) CacheAPI {
+
+	cache := this.clone()
+
+	if item, ok := this.L2.Expired(second).(*RedisClass); ok {
+		cache.Body.Expired = item.Body.Expired
+	}
+
+	return cache
+}
+
+// Stale - 允许过期后继续返回旧值的时长（0 表示关闭）
+func (this *TieredClass) Stale(second any) CacheAPI {
+
+	cache := this.clone()
+	cache.Body.Stale = CacheInst.duration(second)
+
+	return cache
+}
+
+// Negative - 空结果的缓存时长（0 表示关闭）
+func (this *TieredClass) Negative(second any) CacheAPI {
+
+	cache := this.clone()
+	cache.Body.Negative = CacheInst.duration(second)
+
+	return cache
+}
+
+// body - 当前链式参数
+func (this *TieredClass) body() CacheBody { return this.Body }
+
+// Has - 判断缓存是否存在
+func (this *TieredClass) Has(key string) (ok bool) {
+	ok, _ = this.HasCtx(context.Background(), key)
+	return ok
+}
+
+// Get - 获取缓存
+func (this *TieredClass) Get(key string) (value any) {
+	value, _ = this.GetCtx(context.Background(), key)
+	return value
+}
+
+// Set - 设置缓存
+func (this *TieredClass) Set(key string, value any) (ok bool) {
+	return this.SetCtx(context.Background(), key, value) == nil
+}
+
+// Delete - 删除缓存
+func (this *TieredClass) Delete(key ...string) (ok bool) {
+	return this.DeleteCtx(context.Background(), key...) == nil
+}
+
+// Clear - 清空缓存
+func (this *TieredClass) Clear() (ok bool) {
+	return this.ClearCtx(context.Background()) == nil
+}
+
+// HasCtx - 判断缓存是否存在（支持上下文）
+func (this *TieredClass) HasCtx(ctx context.Context, key string) (ok bool, err error) {
+
+	if ok, err = this.L1.HasCtx(ctx, key); ok || err != nil { return ok, err }
+
+	return this.L2.HasCtx(ctx, key)
+}
+
+// GetCtx - 获取缓存（支持上下文）
+func (this *TieredClass) GetCtx(ctx context.Context, key string) (value any, err error) {
+
+	data, err := this.GetRaw(ctx, key)
+	if err != nil { return nil, err }
+
+	if err = CacheInst.decode(this.Codec(), key, data, &value); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCacheDecode, err)
+	}
+
+	return value, nil
+}
+
+// SetCtx - 设置缓存（支持上下文）
+func (this *TieredClass) SetCtx(ctx context.Context, key string, value any) (err error) {
+
+	data, err := CacheInst.encode(this.Codec(), key, value)
+	if err != nil { return fmt.Errorf("编码缓存失败: %w", err) }
+
+	return this.SetRaw(ctx, key, data)
+}
+
+// GetRaw - 获取编码后的原始缓存值
+func (this *TieredClass) GetRaw(ctx context.Context, key string) (data []byte, err error) {
+
+	start := time.Now()
+
+	data, err = this.L1.GetRaw(ctx, key)
+	if err == nil || !errors.Is(err, ErrCacheMiss) {
+		this.counter.read(key, start, err)
+		return data, err
+	}
+
+	// 读取 L2 之前记录失效版本
+	version := this.bus.version(this.Name(key))
+
+	data, err = this.L2.GetRaw(ctx, key)
+	this.counter.read(key, start, err)
+	if err != nil { return nil, err }
+
+	// 回填 L1
+	l1, _ := this.layers()
+	l1.Body.Tags = nil
+	this.backfill(ctx, l1, key, data, version)
+
+	return data, nil
+}
+
+// backfill - 把 L2 读到的值回填 L1，读取期间收到失效通知时放弃回填
+/**
+ * @param version 读取 L2 之前的失效版本
+ */
+func (this *TieredClass) backfill(ctx context.Context, l1 *MemoryClass, key string, data []byte, version uint64) {
+
+	name := this.Name(key)
+	if this.bus.version(name) != version { return }
+
+	_ = l1.SetRaw(ctx, key, data)
+
+	// 写入 L1 的同时到达的失效通知可能已经执行完丢弃，这里再检查一次
+	if this.bus.version(name) != version { l1.Store.delete(name) }
+}
+
+// SetRaw - 写入编码后的原始缓存值
+func (this *TieredClass) SetRaw(ctx context.Context, key string, data []byte) (err error) {
+
+	if utils.Is.Empty(key) { return ErrCacheKeyEmpty }
+
+	l1, l2 := this.layers()
+	start  := time.Now()
+
+	err = l2.SetRaw(ctx, key, data)
+	this.counter.write(key, start, err)
+	if err != nil { return err }
+
+	name := this.Name(key)
+	this.bus.invalidate(name)
+	_ = l1.SetRaw(ctx, key, data)
+
+	this.publish(ctx, tieredMessage{Names: []string{name}})
+
+	return nil
+}
+
+// DeleteCtx - 删除缓存（支持上下文）
+func (this *TieredClass) DeleteCtx(ctx context.Context, key ...string) (err error) {
+
+	cache := this.clone()
+	if cache == nil { return fmt.Errorf("二级缓存未初始化") }
+
+	if cloned, ok := cache.Keys(key).(*TieredClass); ok {
+		cache = cloned
+	}
+
+	l1, l2 := cache.layers()
+	start  := time.Now()
+
+	// 其他节点的 L1 可能是从 L2 回填的，没有标签信息，需要把标签成员一起通知出去
+	names := CacheInst.cloneStrings(cache.Body.Keys)
+	for _, tag := range cache.Body.Tags {
+		members, err := l2.tagMembers(ctx, tag)
+		if err != nil { return err }
+		names = append(names, members...)
+	}
+
+	err = l2.DeleteCtx(ctx)
+	cache.counter.remove(key, cache.Body.Tags, start, err)
+	if err != nil { return err }
+	cache.bus.invalidate(names...)
+	if len(cache.Body.Tags) > 0 { cache.bus.invalidate() }
+	_ = l1.DeleteCtx(ctx)
+	l1.Store.delete(names...)
+
+	cache.publish(ctx, tieredMessage{Names: names, Tags: cache.Body.Tags})
+
+	return nil
+}
+
+// ClearCtx - 清空缓存（支持上下文）
+func (this *TieredClass) ClearCtx(ctx context.Context) (err error) {
+
+	if err = this.L2.ClearCtx(ctx); err != nil { return err }
+
+	this.bus.invalidate()
+	this.L1.Store.clear()
+
+	this.publish(ctx, tieredMessage{Clear: true})
+
+	return nil
+}
+
+// TTL - 获取缓存剩余存活时间（以 L2 为准）
+func (this *TieredClass) TTL(key string) (ttl time.Duration, err error) {
+	return this.L2.TTL(key)
+}
+
+// Touch - 重新设置缓存的过期时间
+/**
+ * 只更新 L2，并丢弃各节点的 L1 副本，下次读取时按新的过期时间回填。
+ */
+func (this *TieredClass) Touch(key string, ttl any) (ok bool) {
+
+	if !this.L2.Touch(key, ttl) { return false }
+
+	name := this.Name(key)
+	this.bus.invalidate(name)
+	this.L1.Store.delete(name)
+	this.publish(context.Background(), tieredMessage{Names: []string{name}})
+
+	return true
+}
+
+// GetMany - 批量获取缓存（L1 未命中的 key 通过一次批量请求从 L2 读取并回填）
+func (this *TieredClass) GetMany(keys []string) (values map[string]any) {
+
+	ctx   := context.Background()
+	items := make(map[string][]byte, len(keys))
+	start := time.Now()
+
+	var misses []string
+	for _, key := range keys {
+		data, err := this.L1.GetRaw(ctx, key)
+		if err != nil {
+			misses = append(misses, key)
+			continue
+		}
+		this.counter.read(key, start, nil)
+		items[key] = data
+	}
+
+	if len(misses) > 0 {
+
+		l1, l2 := this.layers()
+		l1.Body.Tags = nil
+
+		// 读取 L2 之前记录失效版本
+		versions := make(map[string]uint64, len(misses))
+		for _, key := range misses { versions[key] = this.bus.version(this.Name(key)) }
+
+		found, err := l2.getRaws(ctx, misses)
+		if err != nil {
+			for _, key := range misses { this.counter.read(key, start, err) }
+			found = map[string][]byte{}
+		}
+
+		for _, key := range misses {
+			data, ok := found[key]
+			if !ok {
+				if err == nil { this.counter.read(key, start, ErrCacheMiss) }
+				continue
+			}
+			this.counter.read(key, start, nil)
+			items[key] = data
+			// 回填 L1
+			this.backfill(ctx, l1.clone(), key, data, versions[key])
+		}
+	}
+
+	return CacheInst.decodeMany(this.Codec(), items)
+}
+
+// SetMany - 批量设置缓存
+func (this *TieredClass) SetMany(values map[string]any) (ok bool) {
+
+	ctx := context.Background()
+
+	items, err := CacheInst.encodeMany(this.Codec(), values)
+	if err != nil { return false }
+
+	l1, l2 := this.layers()
+	start  := time.Now()
+
+	err = l2.setRaws(ctx, items)
+	for key := range items { this.counter.write(key, start, err) }
+	if err != nil { return false }
+
+	names := make([]string, 0, len(items))
+	for key := range items {
+		names = append(names, this.Name(key))
+	}
+	this.bus.invalidate(names...)
+
+	for key, data := range items {
+		_ = l1.clone().SetRaw(ctx, key, data)
+	}
+
+	this.publish(ctx, tieredMessage{Names: names})
+
+	return true
+}
+
+// DeleteByPattern - 按通配符删除缓存（以 L2 为准）
+func (this *TieredClass) DeleteByPattern(pattern string) (count int, err error) {
+
+	keys, err := this.L2.Scan(pattern)
+	if err != nil || len(keys) == 0 { return 0, err }
+
+	if err = this.clone().DeleteCtx(context.Background(), CacheInst.markers(keys)...); err != nil { return 0, err }
+
+	return len(keys), nil
+}
+
+// Export - 以 JSON Lines 导出所有未过期的缓存（以 L2 为准）
+func (this *TieredClass) Export(w io.Writer) (count int, err error) {
+	return this.L2.Export(w)
+}
+
+// Import - 导入 Export 导出的缓存（写入 L2 并通知各节点）
+func (this *TieredClass) Import(r io.Reader) (count int, err error) {
+	return CacheInst.load(this, r)
+}
+
+// Incr - 原子自增
+func (this *TieredClass) Incr(key string, delta int64) (value int64, err error) {
+	return this.IncrWithTTL(key, delta, nil)
+}
+
+// Decr - 原子自减
+func (this *TieredClass) Decr(key string, delta int64) (value int64, err error) {
+	return this.IncrWithTTL(key, -delta, nil)
+}
+
+// IncrWithTTL - 原子自增，仅在首次创建时设置过期时间
+/**
+ * 计数只在 L2 上进行，并丢弃各节点的 L1 副本，避免读到旧值。
+ */
+func (this *TieredClass) IncrWithTTL(key string, delta int64, ttl any) (value int64, err error) {
+
+	_, l2 := this.layers()
+
+	if value, err = l2.IncrWithTTL(key, delta, ttl); err != nil { return value, err }
+
+	name := this.Name(key)
+	this.bus.invalidate(name)
+	this.L1.Store.delete(name)
+
+	this.publish(context.Background(), tieredMessage{Names: []string{name}})
+
+	return value, nil
+}
+
+// Scan - 按通配符列出缓存的原始 key（以 L2 为准）
+func (this *TieredClass) Scan(pattern string) (keys []string, err error) {
+	return this.L2.Scan(pattern)
+}
+
+// Stats - 缓存统计（命中率统计两级合计，条数和容量以 L2 为准）
+func (this *TieredClass) Stats() (stats CacheStats) {
+
+	item := this.L2.Stats()
+
+	stats = this.counter.stats("tiered")
+	stats.Keys, stats.Size = item.Keys, item.Size
+
+	return stats
+}
+
+// Codec - 当前使用的编解码器
+func (this *TieredClass) Codec() CacheCodec {
+	if this.codec == nil { return CacheInst.codec("json") }
+	return this.codec
+}
+
+// Remember - 读取缓存，未命中时回源并写入
+func (this *TieredClass) Remember(key string, ttl any, fn func() (any, error)) (value any, err error) {
+	return CacheRemember[any](this, key, ttl, fn)
+}
+
+// flight - 进程内请求合并组
+func (this *TieredClass) flight() *singleflight.Group {
+	if this.group == nil { this.group = &singleflight.Group{} }
+	return this.group
+}
+
+// lock - 使用 L2 的分布式锁
+func (this *TieredClass) lock(ctx context.Context, key string) (unlock func(), ok bool, err error) {
+	return this.L2.lock(ctx, key)
+}
+
+// lockWait - 使用 L2 的等待时间
+func (this *TieredClass) lockWait() time.Duration { return this.L2.lockWait() }
+
+// Name - 缓存名称规则（与 L1 / L2 保持一致） - 辅助方法
+func (this *TieredClass) Name(key string) string {
+	return this.L2.Name(key)
+}
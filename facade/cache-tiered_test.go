@@ -0,0 +1,118 @@
+package facade
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/inis-io/aide/dto"
+)
+
+// newTieredCache - 创建连接到 miniredis 的二级缓存
+func newTieredCache(t *testing.T, server *miniredis.Miniredis) *TieredClass {
+
+	t.Helper()
+
+	port, _ := strconv.Atoi(server.Port())
+	cache, ok := CacheInst.newWithConfig(dto.CacheConfig{
+		Engine: "tiered",
+		Redis:  dto.CacheRedisConfig{Host: server.Host(), Port: port},
+	}).(*TieredClass)
+	if !ok { t.Fatal("expected tiered cache") }
+	t.Cleanup(cache.Close)
+
+	return cache
+}
+
+// eventually - 在超时前等待条件成立
+func eventually(t *testing.T, message string, fn func() bool) {
+
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !fn() {
+		if time.Now().After(deadline) { t.Fatal(message) }
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// waitSubscribed - 等待两个节点都订阅了失效通知
+func waitSubscribed(t *testing.T, server *miniredis.Miniredis, channel string) {
+	eventually(t, "timeout waiting for subscribers", func() bool {
+		return server.PubSubNumSub(channel)[channel] == 2
+	})
+}
+
+func TestTieredCrossInstanceInvalidation(t *testing.T) {
+
+	server := miniredis.RunT(t)
+	a, b   := newTieredCache(t, server), newTieredCache(t, server)
+	waitSubscribed(t, server, a.Config.Channel)
+
+	if !a.Set("user", "alice") { t.Fatal("set failed") }
+
+	// b 从 L2 读取并回填 L1（a 写入时发出的通知可能让第一次回填被放弃）
+	eventually(t, "b never backfilled L1", func() bool {
+		if value := b.Get("user"); value != "alice" { t.Fatalf("unexpected value: %v", value) }
+		_, err := b.L1.GetRaw(context.Background(), "user")
+		return err == nil
+	})
+
+	a.Set("user", "bob")
+	eventually(t, "b still serves the old L1 value after Set", func() bool { return b.Get("user") == "bob" })
+
+	a.Delete("user")
+	eventually(t, "b still serves the L1 value after Delete", func() bool { return b.Get("user") == nil })
+}
+
+func TestTieredTagInvalidation(t *testing.T) {
+
+	server := miniredis.RunT(t)
+	a, b   := newTieredCache(t, server), newTieredCache(t, server)
+	waitSubscribed(t, server, a.Config.Channel)
+
+	a.Tag("user").Set("alice", 1)
+	a.Tag("user").Set("bob", 2)
+	a.Set("carol", 3)
+
+	// b 回填的 L1 条目没有标签信息
+	for _, key := range []string{"alice", "bob", "carol"} {
+		if b.Get(key) == nil { t.Fatalf("expected %v in b", key) }
+	}
+
+	if !a.Tag("user").Delete() { t.Fatal("tag delete failed") }
+
+	eventually(t, "b still serves tagged values after tag Delete", func() bool {
+		return b.Get("alice") == nil && b.Get("bob") == nil
+	})
+	if b.Get("carol") == nil { t.Fatal("untagged value was removed") }
+}
+
+func TestTieredBackfillSkipsAfterInvalidation(t *testing.T) {
+
+	server := miniredis.RunT(t)
+	cache  := newTieredCache(t, server)
+	ctx    := context.Background()
+
+	l1, _ := cache.layers()
+	name  := cache.Name("user")
+
+	// 读取 L2 期间收到失效通知，旧值不能写入 L1
+	version := cache.bus.version(name)
+	cache.bus.invalidate(name)
+	cache.backfill(ctx, l1, "user", []byte(`"alice"`), version)
+	if _, err := cache.L1.GetRaw(ctx, "user"); !errors.Is(err, ErrCacheMiss) { t.Fatalf("expected L1 miss, got %v", err) }
+
+	// 标签删除时整体递增版本
+	version = cache.bus.version(name)
+	cache.bus.invalidate()
+	cache.backfill(ctx, l1, "user", []byte(`"alice"`), version)
+	if _, err := cache.L1.GetRaw(ctx, "user"); !errors.Is(err, ErrCacheMiss) { t.Fatalf("expected L1 miss, got %v", err) }
+
+	// 没有失效通知时正常回填
+	cache.backfill(ctx, l1, "user", []byte(`"alice"`), cache.bus.version(name))
+	if _, err := cache.L1.GetRaw(ctx, "user"); err != nil { t.Fatalf("expected L1 hit, got %v", err) }
+}
@@ -0,0 +1,261 @@
+package facade
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/inis-io/aide/dto"
+	"github.com/klauspost/compress/zstd"
+)
+
+// cacheTransformMagic - 经过压缩或加密的缓存值的头部标记
+/**
+ * 只开启压缩时，没有该标记的值按原样交给编解码器，开启前写入的缓存可以继续读取；
+ * 开启加密后没有该标记的值会被拒绝（Encrypt.Migrate 为 true 时除外）。
+ */
+var cacheTransformMagic = []byte{0x00, 'I', 'C', 0x01}
+
+const (
+	// cacheTransformGzip - gzip 压缩
+	cacheTransformGzip byte = 1 << iota
+	// cacheTransformZstd - zstd 压缩
+	cacheTransformZstd
+	// cacheTransformAES - AES-GCM 加密
+	cacheTransformAES
+)
+
+// CacheTransformCodec - 在编解码器外层透明地压缩、加密缓存值
+/**
+ * 写入顺序：编码 -> 压缩（超过阈值时） -> 加密；读取时反向处理。
+ * 加密时头部标志位与缓存 key 作为 GCM 附加数据参与认证，引擎通过 CacheInst.encode / decode 传入 key；
+ * 直接调用 Marshal / Unmarshal 时按空 key 处理。
+ */
+type CacheTransformCodec struct {
+	// 内层编解码器
+	Codec     CacheCodec
+	// 压缩算法
+	algorithm string
+	// 压缩阈值
+	threshold int
+	// 加密器
+	aead      cipher.AEAD
+	// 是否允许读取未加密的旧值
+	migrate   bool
+	// 配置错误（密钥无效时拒绝读写，避免明文落盘）
+	err       error
+}
+
+// cacheZstdEncoder - 共享的 zstd 编码器（EncodeAll 可并发调用）
+var cacheZstdEncoder = sync.OnceValues(func() (*zstd.Encoder, error) { return zstd.NewWriter(nil) })
+
+// cacheZstdDecoder - 共享的 zstd 解码器（DecodeAll 可并发调用）
+var cacheZstdDecoder = sync.OnceValues(func() (*zstd.Decoder, error) { return zstd.NewReader(nil) })
+
+// transform - 按配置包装编解码器，未开启压缩和加密时原样返回
+func (this *CacheClass) transform(codec CacheCodec, compress dto.CacheCompressConfig, encrypt dto.CacheEncryptConfig) CacheCodec {
+
+	if compress.Algorithm == "" && encrypt.Key == "" { return codec }
+
+	item := &CacheTransformCodec{
+		Codec:     codec,
+		algorithm: compress.Algorithm,
+		threshold: compress.Threshold,
+		migrate:   encrypt.Migrate,
+	}
+
+	if item.algorithm != "" && item.algorithm != "gzip" && item.algorithm != "zstd" {
+		item.err = fmt.Errorf("不支持的压缩算法: %s", item.algorithm)
+	}
+
+	if encrypt.Key != "" && item.err == nil {
+		item.aead, item.err = this.aead(encrypt.Key, encrypt.Encoding)
+	}
+
+	return item
+}
+
+// aead - 解析密钥并创建 AES-GCM 加密器
+func (this *CacheClass) aead(key, encoding string) (aead cipher.AEAD, err error) {
+
+	secret, err := this.secret(key, strings.ToLower(strings.TrimSpace(encoding)))
+	if err != nil { return nil, err }
+
+	block, err := aes.NewCipher(secret)
+	if err != nil { return nil, fmt.Errorf("初始化 AES 失败: %w", err) }
+
+	return cipher.NewGCM(block)
+}
+
+// secret - 按编码解析密钥
+/**
+ * 未指定编码时先尝试解码：32 个字符的 hex 与 24 个字符的 base64 同时也是合法的原始密钥长度，
+ * 先按原始字节判断会导致编码后的密钥永远不会被解码。
+ */
+func (this *CacheClass) secret(key, encoding string) (secret []byte, err error) {
+
+	switch encoding {
+	case "raw":
+		secret = []byte(key)
+	case "hex":
+		if secret, err = hex.DecodeString(key); err != nil { return nil, fmt.Errorf("加密密钥不是合法的 hex 编码: %w", err) }
+	case "base64":
+		if secret, err = base64.StdEncoding.DecodeString(key); err != nil { return nil, fmt.Errorf("加密密钥不是合法的 base64 编码: %w", err) }
+	case "":
+		if item, err := hex.DecodeString(key); err == nil && this.validKey(item) {
+			secret = item
+		} else if item, err := base64.StdEncoding.DecodeString(key); err == nil && this.validKey(item) {
+			secret = item
+		} else {
+			secret = []byte(key)
+		}
+	default:
+		return nil, fmt.Errorf("不支持的密钥编码: %s", encoding)
+	}
+
+	if !this.validKey(secret) { return nil, fmt.Errorf("加密密钥长度必须为 16、24 或 32 字节") }
+
+	return secret, nil
+}
+
+// validKey - 是否为合法的 AES 密钥长度
+func (this *CacheClass) validKey(key []byte) bool {
+	return len(key) == 16 || len(key) == 24 || len(key) == 32
+}
+
+// Name - 编解码器名称（与内层编解码器一致）
+func (this *CacheTransformCodec) Name() string { return this.Codec.Name() }
+
+// Marshal - 编码后压缩、加密
+func (this *CacheTransformCodec) Marshal(value any) (data []byte, err error) {
+
+	if this.err != nil { return nil, this.err }
+
+	if data, err = this.Codec.Marshal(value); err != nil { return nil, err }
+
+	return this.seal("", data)
+}
+
+// Unmarshal - 解密、解压后解码
+func (this *CacheTransformCodec) Unmarshal(data []byte, value any) (err error) {
+
+	if data, err = this.open("", data); err != nil { return err }
+
+	return this.Codec.Unmarshal(data, value)
+}
+
+// seal - 压缩、加密已编码的字节
+/**
+ * @param key 缓存的key（加密时作为附加数据，密文不能挪到其他 key 下使用）
+ */
+func (this *CacheTransformCodec) seal(key string, data []byte) (result []byte, err error) {
+
+	if this.err != nil { return nil, this.err }
+
+	var flags byte
+
+	if this.algorithm != "" && len(data) >= this.threshold {
+		if data, err = this.compress(data); err != nil { return nil, fmt.Errorf("压缩缓存失败: %w", err) }
+		flags |= map[string]byte{"gzip": cacheTransformGzip, "zstd": cacheTransformZstd}[this.algorithm]
+	}
+
+	if this.aead != nil {
+		nonce := make([]byte, this.aead.NonceSize())
+		if _, err = rand.Read(nonce); err != nil { return nil, fmt.Errorf("生成随机数失败: %w", err) }
+		flags |= cacheTransformAES
+		data   = this.aead.Seal(nonce, nonce, data, this.additional(flags, key))
+	}
+
+	// 既未压缩也未加密（低于阈值）时保持原样
+	if flags == 0 { return data, nil }
+
+	return append(append(bytes.Clone(cacheTransformMagic), flags), data...), nil
+}
+
+// open - 还原为编码后的字节
+func (this *CacheTransformCodec) open(key string, data []byte) (result []byte, err error) {
+
+	if this.err != nil { return nil, this.err }
+
+	// 没有头部标记：开启前写入的旧值；开启加密时未经认证的值不可信，除非处于迁移模式
+	if !bytes.HasPrefix(data, cacheTransformMagic) || len(data) <= len(cacheTransformMagic) {
+		if this.aead != nil && !this.migrate { return nil, fmt.Errorf("缓存值未加密，已拒绝读取") }
+		return data, nil
+	}
+
+	flags := data[len(cacheTransformMagic)]
+	data   = data[len(cacheTransformMagic) + 1:]
+
+	// 只有头部标记、没有加密标志的值同样未经认证
+	if flags & cacheTransformAES == 0 && this.aead != nil && !this.migrate {
+		return nil, fmt.Errorf("缓存值未加密，已拒绝读取")
+	}
+
+	if flags & cacheTransformAES != 0 {
+		if this.aead == nil { return nil, fmt.Errorf("缓存值已加密，但未配置密钥") }
+		size := this.aead.NonceSize()
+		if len(data) < size { return nil, fmt.Errorf("缓存值已损坏") }
+		if data, err = this.aead.Open(nil, data[:size], data[size:], this.additional(flags, key)); err != nil {
+			return nil, fmt.Errorf("解密缓存失败: %w", err)
+		}
+	}
+
+	switch {
+	case flags & cacheTransformGzip != 0:
+		data, err = this.gunzip(data)
+	case flags & cacheTransformZstd != 0:
+		data, err = this.unzstd(data)
+	}
+	if err != nil { return nil, fmt.Errorf("解压缓存失败: %w", err) }
+
+	return data, nil
+}
+
+// additional - GCM 附加数据：头部标记 + 标志位 + 缓存 key
+func (this *CacheTransformCodec) additional(flags byte, key string) []byte {
+	return append(append(bytes.Clone(cacheTransformMagic), flags), key...)
+}
+
+// compress - 按配置的算法压缩
+func (this *CacheTransformCodec) compress(data []byte) (result []byte, err error) {
+
+	if this.algorithm == "zstd" {
+		encoder, err := cacheZstdEncoder()
+		if err != nil { return nil, err }
+		return encoder.EncodeAll(data, nil), nil
+	}
+
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	if _, err = writer.Write(data); err != nil { return nil, err }
+	if err = writer.Close(); err != nil { return nil, err }
+
+	return buffer.Bytes(), nil
+}
+
+// gunzip - gzip 解压
+func (this *CacheTransformCodec) gunzip(data []byte) (result []byte, err error) {
+
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil { return nil, err }
+	defer func() { _ = reader.Close() }()
+
+	return io.ReadAll(reader)
+}
+
+// unzstd - zstd 解压
+func (this *CacheTransformCodec) unzstd(data []byte) (result []byte, err error) {
+
+	decoder, err := cacheZstdDecoder()
+	if err != nil { return nil, err }
+
+	return decoder.DecodeAll(data, nil)
+}
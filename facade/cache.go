@@ -2,10 +2,21 @@ package facade
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	JSON "encoding/json"
+	"errors"
 	"fmt"
+	"hash/fnv"
 	"io"
+	"math"
 	"os"
+	"path"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 	
 	"github.com/inis-io/aide/dto"
@@ -14,6 +25,7 @@ import (
 	"github.com/redis/go-redis/v9/maintnotifications"
 	"github.com/spf13/afero"
 	"github.com/spf13/cast"
+	"golang.org/x/sync/singleflight"
 )
 
 // CacheInst - 缓存配置控制器实例
@@ -30,6 +42,8 @@ type CacheClass struct {
 	Config    dto.CacheConfig `json:"config"`
 	// 是否已经注入过配置
 	HasConfig bool            `json:"hasConfig"`
+	// 最近一次初始化时的配置错误（如加密密钥、压缩算法、TLS 证书无效），同时会写入 error 日志
+	Err       error           `json:"-"`
 }
 
 // normConfig 统一配置默认值，避免不同项目接入时行为不一致
@@ -39,10 +53,17 @@ func (this *CacheClass) normConfig(config dto.CacheConfig) dto.CacheConfig {
 	if utils.Is.Empty(config.Engine) {
 		config.Engine = "file"
 	}
-	if config.Engine != "file" && config.Engine != "redis" {
+	switch config.Engine {
+	case "file", "redis", "memory", "tiered":
+	default:
 		config.Engine = "file"
 	}
 	
+	config.Codec = strings.ToLower(strings.TrimSpace(config.Codec))
+	if utils.Is.Empty(config.Codec) {
+		config.Codec = "json"
+	}
+	
 	if utils.Is.Empty(config.Redis.Host) {
 		config.Redis.Host = "127.0.0.1"
 	}
@@ -55,6 +76,18 @@ func (this *CacheClass) normConfig(config dto.CacheConfig) dto.CacheConfig {
 	if utils.Is.Empty(config.Redis.Prefix) {
 		config.Redis.Prefix = "INIS"
 	}
+	if config.Redis.LockWait <= 0 {
+		config.Redis.LockWait = 10
+	}
+	config.Redis.Mode = strings.ToLower(strings.TrimSpace(config.Redis.Mode))
+	switch config.Redis.Mode {
+	case "standalone", "sentinel", "cluster":
+	default:
+		config.Redis.Mode = "standalone"
+	}
+	if config.Redis.Mode == "sentinel" && utils.Is.Empty(config.Redis.MasterName) {
+		config.Redis.MasterName = "mymaster"
+	}
 	
 	if utils.Is.Empty(config.File.Root) {
 		config.File.Root = "./runtime/cache"
@@ -69,6 +102,34 @@ func (this *CacheClass) normConfig(config dto.CacheConfig) dto.CacheConfig {
 		config.File.Prefix = "INIS"
 	}
 	
+	config.Compress.Algorithm = strings.ToLower(strings.TrimSpace(config.Compress.Algorithm))
+	if config.Compress.Threshold <= 0 {
+		config.Compress.Threshold = 1024
+	}
+	
+	if config.Memory.Expired <= 0 {
+		config.Memory.Expired = 7200
+	}
+	if utils.Is.Empty(config.Memory.Prefix) {
+		config.Memory.Prefix = "INIS"
+	}
+	if config.Memory.MaxEntries < 0 {
+		config.Memory.MaxEntries = 0
+	}
+	if config.Memory.MaxEntries == 0 && config.Memory.MaxBytes <= 0 {
+		config.Memory.MaxEntries = 10000
+	}
+	
+	if config.Tiered.Expired <= 0 {
+		config.Tiered.Expired = 60
+	}
+	if config.Tiered.MaxEntries <= 0 && config.Tiered.MaxBytes <= 0 {
+		config.Tiered.MaxEntries = 1000
+	}
+	if utils.Is.Empty(config.Tiered.Channel) {
+		config.Tiered.Channel = fmt.Sprintf("%s-CACHE-INVALIDATE", config.Redis.Prefix)
+	}
+	
 	if utils.Is.Empty(config.Hash) {
 		config.Hash = utils.Hash.Sum32(utils.Json.Encode(config))
 	}
@@ -90,20 +151,90 @@ func (this *CacheClass) useDefaultCache() {
 // setActiveCache - 按配置切换当前活动缓存实现
 func (this *CacheClass) setActiveCache(config dto.CacheConfig) {
 	
-	conf := CacheInst.normConfig(config)
+	// 停止旧实例的后台协程（二级缓存订阅、文件缓存清理），避免切换配置后协程泄漏
+	if item, ok := Cache.(cacheCloser); ok { item.Close() }
+	
+	Cache, this.Err = CacheInst.build(config)
+	
+	Redis       = nil
+	FileCache   = nil
+	MemoryCache = nil
+	TieredCache = nil
+	
+	switch impl := Cache.(type) {
+	case *RedisClass:
+		Redis = impl
+	case *FileClass:
+		FileCache = impl
+	case *MemoryClass:
+		MemoryCache = impl
+	case *TieredClass:
+		TieredCache = impl
+	}
+}
+
+// cacheCloser - 持有后台协程、需要显式关闭的缓存实现
+type cacheCloser interface {
+	Close()
+}
+
+// newWithConfig - 按配置创建新的缓存实现
+func (this *CacheClass) newWithConfig(config dto.CacheConfig) CacheAPI {
+	cache, _ := this.build(config)
+	return cache
+}
 
+// build - 按配置创建缓存实现，配置错误会写入 error 日志并返回
+/**
+ * 配置错误时仍然返回可用的实例（读写会失败），与之前的行为保持一致。
+ */
+func (this *CacheClass) build(config dto.CacheConfig) (cache CacheAPI, err error) {
+	
+	conf  := CacheInst.normConfig(config)
+	codec := CacheInst.transform(CacheInst.codec(conf.Codec), conf.Compress, conf.Encrypt)
+	
 	switch conf.Engine {
 	case "redis":
-		Redis = &RedisClass{}
-		Redis.Init(conf.Redis)
-		FileCache = nil
-		Cache = Redis
+		item := &RedisClass{codec: codec}
+		item.Init(conf.Redis)
+		cache = item
+	case "memory":
+		item := &MemoryClass{codec: codec}
+		item.Init(conf.Memory)
+		cache = item
+	case "tiered":
+		item := &TieredClass{codec: codec}
+		item.Init(conf.Redis, conf.Tiered)
+		cache = item
 	default:
-		FileCache = &FileClass{}
-		FileCache.Init(conf.File)
-		Redis = nil
-		Cache = FileCache
+		item := &FileClass{codec: codec}
+		item.Init(conf.File)
+		cache = item
+	}
+	
+	if err = this.check(cache, codec); err != nil {
+		LogInst.ensureLog()
+		Log.Error(map[string]any{"engine": conf.Engine, "error": err.Error()}, "缓存配置无效")
+	}
+	
+	return cache, err
+}
+
+// check - 检查初始化时就能发现的配置错误
+func (this *CacheClass) check(cache CacheAPI, codec CacheCodec) (err error) {
+	
+	var errs []error
+	
+	if item, ok := codec.(*CacheTransformCodec); ok && item.err != nil { errs = append(errs, item.err) }
+	
+	switch item := cache.(type) {
+	case *RedisClass:
+		errs = append(errs, item.err)
+	case *TieredClass:
+		errs = append(errs, item.L2.err)
 	}
+	
+	return errors.Join(errs...)
 }
 
 // setConfig - 注入缓存配置
@@ -151,12 +282,30 @@ func (this *CacheClass) Init(config ...dto.CacheConfig) {
  */
 var Cache CacheAPI
 
+// ErrCacheMiss - 缓存不存在（或已过期），用于区分“未命中”与“后端故障”
+var ErrCacheMiss = errors.New("缓存不存在")
+
+// ErrCacheKeyEmpty - 缓存键为空
+var ErrCacheKeyEmpty = errors.New("缓存键不能为空")
+
+// ErrCacheNotFound - 回源数据不存在，配合 Negative 缓存空结果
+var ErrCacheNotFound = errors.New("数据不存在")
+
+// ErrCacheDecode - 缓存值无法解码（编码方式不匹配、数据损坏或未通过加密校验）
+var ErrCacheDecode = errors.New("解码缓存失败")
+
 // Redis - 当前激活的 Redis 缓存实例（当 Engine=redis 时可用）
 var Redis *RedisClass
 
 // FileCache - 当前激活的文件缓存实例（当 Engine=file 时可用）
 var FileCache *FileClass
 
+// MemoryCache - 当前激活的内存缓存实例（当 Engine=memory 时可用）
+var MemoryCache *MemoryClass
+
+// TieredCache - 当前激活的二级缓存实例（当 Engine=tiered 时可用）
+var TieredCache *TieredClass
+
 // CacheAPI - 统一缓存能力接口
 type CacheAPI interface {
 	// Tag
@@ -223,6 +372,174 @@ type CacheAPI interface {
 	 * @return bool
 	 */
 	Clear() (ok bool)
+	// HasCtx
+	/**
+	 * @name 判断缓存是否存在（支持上下文）
+	 * @param ctx 上下文，用于传递超时和取消信号
+	 * @param key 缓存的key
+	 * @return bool, error 后端异常时返回错误
+	 */
+	HasCtx(ctx context.Context, key string) (ok bool, err error)
+	// GetCtx
+	/**
+	 * @name 获取缓存（支持上下文）
+	 * @param ctx 上下文，用于传递超时和取消信号
+	 * @param key 缓存的key
+	 * @return any 缓存值, error 未命中时返回 ErrCacheMiss
+	 */
+	GetCtx(ctx context.Context, key string) (value any, err error)
+	// SetCtx
+	/**
+	 * @name 设置缓存（支持上下文）
+	 * @param ctx 上下文，用于传递超时和取消信号
+	 * @param key 缓存的key
+	 * @param value 缓存的值
+	 * @return error
+	 */
+	SetCtx(ctx context.Context, key string, value any) (err error)
+	// DeleteCtx
+	/**
+	 * @name 删除缓存（支持上下文）
+	 * @param ctx 上下文，用于传递超时和取消信号
+	 * @param key 缓存的key
+	 * @return error
+	 */
+	DeleteCtx(ctx context.Context, key ...string) (err error)
+	// ClearCtx
+	/**
+	 * @name 清空缓存（支持上下文）
+	 * @param ctx 上下文，用于传递超时和取消信号
+	 * @return error
+	 */
+	ClearCtx(ctx context.Context) (err error)
+	// GetRaw
+	/**
+	 * @name 获取编码后的原始缓存值
+	 * @param ctx 上下文
+	 * @param key 缓存的key
+	 * @return []byte 原始字节, error 未命中时返回 ErrCacheMiss
+	 */
+	GetRaw(ctx context.Context, key string) (data []byte, err error)
+	// SetRaw
+	/**
+	 * @name 写入编码后的原始缓存值（沿用链式调用上的过期时间和标签）
+	 * @param ctx 上下文
+	 * @param key 缓存的key
+	 * @param data 原始字节
+	 * @return error
+	 */
+	SetRaw(ctx context.Context, key string, data []byte) (err error)
+	// Codec
+	/**
+	 * @name 当前使用的编解码器
+	 * @return CacheCodec
+	 */
+	Codec() CacheCodec
+	// Remember
+	/**
+	 * @name 读取缓存，未命中时调用 fn 计算并写入（并发未命中只回源一次）
+	 * @param key 缓存的key
+	 * @param ttl 过期时间（为 nil 时沿用链式调用上的过期时间）
+	 * @param fn 回源函数
+	 * @return any 缓存值, error
+	 */
+	Remember(key string, ttl any, fn func() (any, error)) (value any, err error)
+	// TTL
+	/**
+	 * @name 获取缓存剩余存活时间
+	 * @param key 缓存的key
+	 * @return time.Duration 剩余时间（永不过期时为 -1）, error 未命中时返回 ErrCacheMiss
+	 */
+	TTL(key string) (ttl time.Duration, err error)
+	// Touch
+	/**
+	 * @name 重新设置缓存的过期时间（从现在开始计算）
+	 * @param key 缓存的key
+	 * @param ttl 过期时间，规则同 Expired
+	 * @return bool 缓存不存在时返回 false
+	 */
+	Touch(key string, ttl any) (ok bool)
+	// Incr
+	/**
+	 * @name 原子自增（key 不存在时从 0 开始，并使用链式调用上的过期时间）
+	 * 计数器以十进制整数文本存储，读取当前值请使用 Incr(key, 0)
+	 * @param key 缓存的key
+	 * @param delta 增量
+	 * @return int64 自增后的值, error 原值不是整数时返回错误
+	 */
+	Incr(key string, delta int64) (value int64, err error)
+	// Decr
+	/**
+	 * @name 原子自减，规则同 Incr
+	 * @param key 缓存的key
+	 * @param delta 减量
+	 * @return int64 自减后的值, error
+	 */
+	Decr(key string, delta int64) (value int64, err error)
+	// IncrWithTTL
+	/**
+	 * @name 原子自增，仅在 key 首次创建时设置过期时间（适用于限流窗口、验证码尝试次数）
+	 * @param key 缓存的key
+	 * @param delta 增量
+	 * @param ttl 过期时间，规则同 Expired（为 nil 时沿用链式调用上的过期时间）
+	 * @return int64 自增后的值, error
+	 * @example：
+	 * count, err := facade.Cache.IncrWithTTL("login:fail:"+ip, 1, time.Minute)
+	 */
+	IncrWithTTL(key string, delta int64, ttl any) (value int64, err error)
+	// GetMany
+	/**
+	 * @name 批量获取缓存
+	 * @param keys 缓存的key
+	 * @return map[string]any 命中的缓存（未命中或解码失败的 key 不会出现在结果中）
+	 */
+	GetMany(keys []string) (values map[string]any)
+	// SetMany
+	/**
+	 * @name 批量设置缓存（共用链式调用上的过期时间和标签）
+	 * @param values key -> 缓存的值
+	 * @return bool
+	 * @example：
+	 * ok := facade.Cache.Expired(60).Tag("user").SetMany(map[string]any{"user:1": u1, "user:2": u2})
+	 */
+	SetMany(values map[string]any) (ok bool)
+	// DeleteByPattern
+	/**
+	 * @name 按通配符删除缓存，匹配规则同 Scan
+	 * @param pattern 通配符
+	 * @return int 删除的缓存数, error
+	 */
+	DeleteByPattern(pattern string) (count int, err error)
+	// Export
+	/**
+	 * @name 以 JSON Lines 导出所有未过期的缓存（含剩余存活时间和标签）
+	 * @param w 输出
+	 * @return int 导出的条数, error（有条目无法还原时为 *CacheSkipError，其余条目已导出）
+	 * @example：
+	 * file, _ := os.Create("cache.jsonl")
+	 * count, err := facade.Cache.Export(file)
+	 */
+	Export(w io.Writer) (count int, err error)
+	// Import
+	/**
+	 * @name 导入 Export 导出的缓存，可用于不同引擎之间迁移或预热
+	 * @param r 输入
+	 * @return int 导入的条数, error
+	 */
+	Import(r io.Reader) (count int, err error)
+	// Scan
+	/**
+	 * @name 按通配符列出缓存的原始 key（* ? [...]，规则同 path.Match）
+	 * @param pattern 通配符
+	 * @return []string 匹配的 key, error
+	 */
+	Scan(pattern string) (keys []string, err error)
+	// Stats
+	/**
+	 * @name 缓存统计（命中、未命中、条数、占用字节数）
+	 * @return CacheStats
+	 */
+	Stats() (stats CacheStats)
 	// Expired
 	/**
 	 * @name 设置缓存过期时间
@@ -231,6 +548,22 @@ type CacheAPI interface {
 	 * @return CacheAPI
 	 */
 	Expired(second any) CacheAPI
+	// Stale
+	/**
+	 * @name 允许过期后继续返回旧值的时长（stale-while-revalidate），仅对 Remember / CacheRemember 生效
+	 * 旧值返回的同时会在后台回源刷新，规则同 Expired
+	 * @return CacheAPI
+	 * @example：
+	 * user, err := facade.CacheRemember(facade.Cache.Stale(30*time.Second), "user:1", time.Minute, loader)
+	 */
+	Stale(second any) CacheAPI
+	// Negative
+	/**
+	 * @name 缓存空结果的时长，仅对 Remember / CacheRemember 生效
+	 * 回源函数返回 ErrCacheNotFound 时记录空结果，有效期内直接返回 ErrCacheNotFound 而不再回源，规则同 Expired
+	 * @return CacheAPI
+	 */
+	Negative(second any) CacheAPI
 	// NewCache - 新建缓存
 	NewCache(config dto.CacheConfig) CacheAPI
 }
@@ -245,6 +578,86 @@ type CacheBody struct {
 	Prefix string
 	// 过期时间
 	Expired time.Duration
+	// 允许返回旧值的时长
+	Stale time.Duration
+	// 空结果的缓存时长
+	Negative time.Duration
+}
+
+// CacheStats - 缓存统计
+type CacheStats struct {
+	// 引擎
+	Engine string `json:"engine"`
+	// 命中次数（自实例创建以来，Has 不计入）
+	Hits   int64  `json:"hits"`
+	// 未命中次数（自实例创建以来）
+	Misses int64  `json:"misses"`
+	// 缓存条数
+	Keys   int64  `json:"keys"`
+	// 占用字节数（redis 引擎按采样估算）
+	Size   int64  `json:"size"`
+}
+
+// match - 按通配符匹配缓存 key（空表达式匹配全部，Remember 的内部标记不参与匹配）
+func (this *CacheClass) match(pattern, key string) bool {
+	
+	if this.marker(key) { return false }
+	if utils.Is.Empty(pattern) || pattern == "*" { return true }
+	
+	ok, _ := path.Match(pattern, key)
+	return ok
+}
+
+// encode - 编码缓存值（开启加密时把 key 绑定到密文上）
+func (this *CacheClass) encode(codec CacheCodec, key string, value any) (data []byte, err error) {
+	
+	item, ok := codec.(*CacheTransformCodec)
+	if !ok { return codec.Marshal(value) }
+	
+	if item.err != nil { return nil, item.err }
+	if data, err = item.Codec.Marshal(value); err != nil { return nil, err }
+	
+	return item.seal(key, data)
+}
+
+// decode - 解码缓存值（开启加密时校验密文是否属于该 key）
+func (this *CacheClass) decode(codec CacheCodec, key string, data []byte, value any) (err error) {
+	
+	item, ok := codec.(*CacheTransformCodec)
+	if !ok { return codec.Unmarshal(data, value) }
+	
+	if data, err = item.open(key, data); err != nil { return err }
+	
+	return item.Codec.Unmarshal(data, value)
+}
+
+// encodeMany - 批量编码缓存值
+func (this *CacheClass) encodeMany(codec CacheCodec, values map[string]any) (items map[string][]byte, err error) {
+	
+	items = make(map[string][]byte, len(values))
+	
+	for key, value := range values {
+		if utils.Is.Empty(key) { return nil, ErrCacheKeyEmpty }
+		if items[key], err = this.encode(codec, key, value); err != nil {
+			return nil, fmt.Errorf("编码缓存失败: %w", err)
+		}
+	}
+	
+	return items, nil
+}
+
+// decodeMany - 批量解码缓存值，解码失败的 key 会被忽略
+func (this *CacheClass) decodeMany(codec CacheCodec, items map[string][]byte) (values map[string]any) {
+	
+	values = make(map[string]any, len(items))
+	
+	for key, data := range items {
+		var value any
+		if err := this.decode(codec, key, data, &value); err != nil { continue }
+		values[key] = value
+	}
+	
+	return values
 }
 
 // cloneStrings - 克隆字符串切片，避免共享底层数组
@@ -264,13 +677,46 @@ func (this *CacheClass) cloneBody(body CacheBody) CacheBody {
 	return body
 }
 
+// duration - 解析时长（数值按秒，字符串支持 5s / 1m），小于等于 0 时返回 0
+func (this *CacheClass) duration(second any) (value time.Duration) {
+	
+	switch v := second.(type) {
+	case time.Duration:
+		value = v
+	case string:
+		if d, err := time.ParseDuration(v); err == nil {
+			value = d
+		} else if i := cast.ToInt64(v); i > 0 {
+			value = time.Duration(i) * time.Second
+		} else {
+			value = cast.ToDuration(v)
+		}
+	default:
+		if cast.ToInt64(second) > 0 {
+			value = time.Duration(cast.ToInt64(second)) * time.Second
+		} else {
+			value = cast.ToDuration(second)
+		}
+	}
+	
+	return max(value, 0)
+}
+
 // ==================== Redis 缓存 ====================
 
 // RedisClass - Redis缓存
 type RedisClass struct {
-	Client *redis.Client
+	Client redis.UniversalClient
 	Body   CacheBody
 	Config dto.CacheRedisConfig
+	// 编解码器
+	codec  CacheCodec
+	// 请求合并组
+	group  *singleflight.Group
+	// 命中计数器
+	counter *cacheCounter
+	// 初始化错误（如 TLS 证书读取失败）
+	err     error
 }
 
 // clone - 克隆 Redis 缓存实例（共享 client，隔离链式上下文）
@@ -285,19 +731,7 @@ func (this *RedisClass) clone() *RedisClass {
 
 // NewCache - 按配置创建新的缓存实例
 func (this *RedisClass) NewCache(config dto.CacheConfig) CacheAPI {
-	
-	conf := CacheInst.normConfig(config)
-	
-	switch strings.ToLower(conf.Engine) {
-	case "file":
-		cache := &FileClass{}
-		cache.Init(conf.File)
-		return cache
-	default:
-		cache := &RedisClass{}
-		cache.Init(conf.Redis)
-		return cache
-	}
+	return CacheInst.newWithConfig(config)
 }
 
 // Init - 初始化 Redis 缓存
@@ -309,17 +743,141 @@ func (this *RedisClass) Init(config dto.CacheRedisConfig) {
 	if !utils.Is.Empty(prefix) {
 		this.Body.Prefix = prefix
 	}
-	this.Client = redis.NewClient(&redis.Options{
-		Addr:     fmt.Sprintf("%s:%v", this.Config.Host, this.Config.Port),
-		DB:       this.Config.Database,
-		Password: this.Config.Password,
+	
+	addrs := this.Config.Addrs
+	if len(addrs) == 0 {
+		addrs = []string{fmt.Sprintf("%s:%v", this.Config.Host, this.Config.Port)}
+	}
+	
+	// 证书错误会在创建实例时写入 error 日志，连接仍然按开启 TLS 的配置建立
+	tlsConfig, err := this.tlsConfig()
+	this.err = err
+	
+	options := &redis.UniversalOptions{
+		Addrs:            addrs,
+		DB:               this.Config.Database,
+		Username:         this.Config.Username,
+		Password:         this.Config.Password,
+		MasterName:       this.Config.MasterName,
+		SentinelPassword: this.Config.SentinelPassword,
+		PoolSize:         this.Config.PoolSize,
+		MinIdleConns:     this.Config.MinIdleConns,
+		DialTimeout:      time.Duration(this.Config.DialTimeout)  * time.Millisecond,
+		ReadTimeout:      time.Duration(this.Config.ReadTimeout)  * time.Millisecond,
+		WriteTimeout:     time.Duration(this.Config.WriteTimeout) * time.Millisecond,
+		TLSConfig:        tlsConfig,
 		// 明确禁用维护通知
 		// 这可防止客户端发送“CLIENT MAINT_NOTIFICATIONS ON”
 		MaintNotificationsConfig: &maintnotifications.Config{
 			Mode: maintnotifications.ModeDisabled,
 		},
-	})
+	}
+	
+	switch this.Config.Mode {
+	case "cluster":
+		this.Client = redis.NewClusterClient(options.Cluster())
+	case "sentinel":
+		this.Client = redis.NewFailoverClient(options.Failover())
+	default:
+		this.Client = redis.NewClient(options.Simple())
+	}
 	this.Body.Expired = time.Duration(this.Config.Expired) * time.Second
+	this.group   = &singleflight.Group{}
+	this.counter = &cacheCounter{engine: "redis"}
+}
+
+// tlsConfig - 按配置生成 TLS 配置（证书读取失败时保持校验开启，连接会直接失败而不是降级为明文）
+func (this *RedisClass) tlsConfig() (item *tls.Config, err error) {
+	
+	conf := this.Config.TLS
+	if !conf.Enable { return nil, nil }
+	
+	item = &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         conf.ServerName,
+		InsecureSkipVerify: conf.Insecure,
+	}
+	
+	var errs []error
+	
+	if !utils.Is.Empty(conf.CAFile) {
+		item.RootCAs = x509.NewCertPool()
+		if pem, err := os.ReadFile(conf.CAFile); err != nil {
+			errs = append(errs, fmt.Errorf("读取 Redis CA 证书失败: %w", err))
+		} else if !item.RootCAs.AppendCertsFromPEM(pem) {
+			errs = append(errs, fmt.Errorf("Redis CA 证书中没有有效的证书: %s", conf.CAFile))
+		}
+	}
+	
+	if !utils.Is.Empty(conf.CertFile) || !utils.Is.Empty(conf.KeyFile) {
+		if cert, err := tls.LoadX509KeyPair(conf.CertFile, conf.KeyFile); err != nil {
+			errs = append(errs, fmt.Errorf("加载 Redis 客户端证书失败: %w", err))
+		} else {
+			item.Certificates = []tls.Certificate{cert}
+		}
+	}
+	
+	return item, errors.Join(errs...)
+}
+
+// cluster - 是否为集群模式 - 辅助方法
+func (this *RedisClass) cluster() bool {
+	_, ok := this.Client.(*redis.ClusterClient)
+	return ok
+}
+
+// pipeline - 创建管道，非集群模式下使用事务保证原子性 - 辅助方法
+/**
+ * 集群模式下 MULTI 不支持跨 slot，只能退化为普通管道（按节点拆分执行）。
+ */
+func (this *RedisClass) pipeline() redis.Pipeliner {
+	if this.cluster() { return this.Client.Pipeline() }
+	return this.Client.TxPipeline()
+}
+
+// unlink - 批量删除键，集群模式下按单键拆分避免 CROSSSLOT - 辅助方法
+func (this *RedisClass) unlink(ctx context.Context, keys ...string) (err error) {
+	
+	if len(keys) == 0 { return nil }
+	
+	if !this.cluster() { return this.Client.Unlink(ctx, keys...).Err() }
+	
+	pipe := this.Client.Pipeline()
+	for _, key := range keys {
+		pipe.Unlink(ctx, key)
+	}
+	_, err = pipe.Exec(ctx)
+	
+	return err
+}
+
+// scan - 遍历匹配的键，集群模式下遍历所有主节点（回调可能并发执行） - 辅助方法
+func (this *RedisClass) scan(ctx context.Context, match string, fn func(client redis.Cmdable, keys []string) error) (err error) {
+	
+	each := func(ctx context.Context, client redis.Cmdable) error {
+		
+		var cursor uint64
+		
+		for {
+			
+			keys, next, err := client.Scan(ctx, cursor, match, 1000).Result()
+			if err != nil { return err }
+			
+			if len(keys) > 0 {
+				if err = fn(client, keys); err != nil { return err }
+			}
+			
+			if cursor = next; cursor == 0 { return nil }
+		}
+	}
+	
+	if item, ok := this.Client.(*redis.ClusterClient); ok {
+		return item.ForEachMaster(ctx, func(ctx context.Context, client *redis.Client) error {
+			return each(ctx, client)
+		})
+	}
+	
+	return each(ctx, this.Client)
 }
 
 // Key - 键
@@ -420,15 +978,31 @@ func (this *RedisClass) Expired(second any) CacheAPI {
 	return cache
 }
 
-// Has - 判断缓存是否存在
-func (this *RedisClass) Has(key string) (ok bool) {
+// Stale - 允许过期后继续返回旧值的时长（0 表示关闭）
+func (this *RedisClass) Stale(second any) CacheAPI {
 	
-	if utils.Is.Empty(key) { return false }
+	cache := this.clone()
+	cache.Body.Stale = CacheInst.duration(second)
 	
-	ctx := context.Background()
+	return cache
+}
+
+// Negative - 空结果的缓存时长（0 表示关闭）
+func (this *RedisClass) Negative(second any) CacheAPI {
 	
-	result, err := this.Client.Exists(ctx, this.Name(key)).Result()
-	return utils.Ternary[bool](err != nil, false, result == 1)
+	cache := this.clone()
+	cache.Body.Negative = CacheInst.duration(second)
+	
+	return cache
+}
+
+// body - 当前链式参数
+func (this *RedisClass) body() CacheBody { return this.Body }
+
+// Has - 判断缓存是否存在
+func (this *RedisClass) Has(key string) (ok bool) {
+	ok, _ = this.HasCtx(context.Background(), key)
+	return ok
 }
 
 // Get - 获取缓存
@@ -436,513 +1010,1859 @@ func (this *RedisClass) Get(key string) (value any) {
 	
 	if utils.Is.Empty(key) { return false }
 	
-	ctx := context.Background()
-	
-	result, err := this.Client.Get(ctx, this.Name(key)).Result()
-	
-	return utils.Ternary[any](err != nil, nil, utils.Json.Decode(result))
+	value, _ = this.GetCtx(context.Background(), key)
+	return value
 }
 
 // Set - 设置缓存
 func (this *RedisClass) Set(key string, value any) (ok bool) {
-	
-	cache := this.clone()
-	if cache == nil || utils.Is.Empty(key) { return false }
-	
-	ctx := context.Background()
-	
-	// 设置缓存
-	err := cache.Client.Set(ctx, cache.Name(key), utils.Json.Encode(value), cache.Body.Expired).Err()
-	
-	// 设置标签
-	cache.SetTags(key)
-	// 重置配置
-	cache.Reset()
-	
-	return utils.Ternary[bool](err != nil, false, true)
+	return this.SetCtx(context.Background(), key, value) == nil
 }
 
 // Delete - 删除缓存
 func (this *RedisClass) Delete(key ...string) (ok bool) {
+	return this.DeleteCtx(context.Background(), key...) == nil
+}
+
+// Clear - 清空缓存
+func (this *RedisClass) Clear() (ok bool) {
+	return this.ClearCtx(context.Background()) == nil
+}
+
+// HasCtx - 判断缓存是否存在（支持上下文）
+func (this *RedisClass) HasCtx(ctx context.Context, key string) (ok bool, err error) {
 	
-	cache := this.clone()
-	if cache == nil { return false }
+	if utils.Is.Empty(key) { return false, ErrCacheKeyEmpty }
 	
-	var err error
-	ctx := context.Background()
+	result, err := this.Client.Exists(ctx, this.Name(key)).Result()
+	if err != nil { return false, err }
 	
-	// // 根据标签删除缓存
-	// if len(this.Body.Tags) > 0 {
-	// 	for _, tag := range this.Body.Tags {
-	// 		// 获取标签下的所有成员
-	// 		members, _ := this.Client.SMembers(ctx, tag).Result()
-	// 		for _, member := range members {
-	// 			// 删除成员
-	// 			err = this.Client.Del(ctx, member).Err()
-	// 			if err != nil { return false }
-	// 		}
-	// 		// 删除标签
-	// 		err = this.Client.Del(ctx, tag).Err()
-	// 		// 清空标签
-	// 		this.Body.Tags = []string{}
-	// 	}
-	// }
+	return result == 1, nil
+}
+
+// GetCtx - 获取缓存（支持上下文）
+func (this *RedisClass) GetCtx(ctx context.Context, key string) (value any, err error) {
 	
-	if cloned, ok := cache.Keys(key).(*RedisClass); ok {
-		cache = cloned
+	data, err := this.GetRaw(ctx, key)
+	if err != nil { return nil, err }
+	
+	if err = CacheInst.decode(this.Codec(), key, data, &value); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCacheDecode, err)
 	}
 	
-	// 根据键集删除缓存
-	_ = cache.Client.Del(ctx, cache.Body.Keys...).Err()
+	return value, nil
+}
+
+// SetCtx - 设置缓存（支持上下文）
+func (this *RedisClass) SetCtx(ctx context.Context, key string, value any) (err error) {
 	
-	// 根据标签删除缓存
-	cache.DelTags()
-	// 重置配置
-	cache.Reset()
+	data, err := CacheInst.encode(this.Codec(), key, value)
+	if err != nil { return fmt.Errorf("编码缓存失败: %w", err) }
 	
-	return utils.Ternary[bool](err != nil, false, true)
+	return this.SetRaw(ctx, key, data)
 }
 
-// Clear - 清空缓存
-func (this *RedisClass) Clear() (ok bool) {
+// GetRaw - 获取编码后的原始缓存值
+func (this *RedisClass) GetRaw(ctx context.Context, key string) (data []byte, err error) {
+	
+	if utils.Is.Empty(key) { return nil, ErrCacheKeyEmpty }
+	
+	start := time.Now()
+	data, err = this.Client.Get(ctx, this.Name(key)).Bytes()
+	if errors.Is(err, redis.Nil) { err = ErrCacheMiss }
+	
+	this.counter.read(key, start, err)
+	if err != nil { return nil, err }
+	
+	return data, nil
+}
+
+// SetRaw - 写入编码后的原始缓存值
+func (this *RedisClass) SetRaw(ctx context.Context, key string, data []byte) (err error) {
+	
+	if utils.Is.Empty(key) { return ErrCacheKeyEmpty }
+	
+	return this.setRaws(ctx, map[string][]byte{key: data})
+}
+
+// getRaws - 批量获取编码后的原始缓存值（集群模式下使用管道逐个 GET，避免跨 slot） - 辅助方法
+func (this *RedisClass) getRaws(ctx context.Context, keys []string) (items map[string][]byte, err error) {
+	
+	items = make(map[string][]byte, len(keys))
+	if len(keys) == 0 { return items, nil }
+	
+	names := make([]string, len(keys))
+	for i, key := range keys {
+		names[i] = this.Name(key)
+	}
+	
+	values := make([]any, len(keys))
+	start  := time.Now()
+	
+	// 后端错误时每个 key 都上报一次
+	defer func() {
+		if err == nil { return }
+		for _, key := range keys { this.counter.read(key, start, err) }
+	}()
+	
+	if this.cluster() {
+		
+		pipe := this.Client.Pipeline()
+		cmds := make([]*redis.StringCmd, len(names))
+		for i, name := range names {
+			cmds[i] = pipe.Get(ctx, name)
+		}
+		if _, err = pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) { return nil, err }
+		
+		for i, cmd := range cmds {
+			if cmd.Err() == nil { values[i] = cmd.Val() }
+		}
+		
+	} else if values, err = this.Client.MGet(ctx, names...).Result(); err != nil {
+		return nil, err
+	}
+	
+	for i, value := range values {
+		
+		item, ok := value.(string)
+		if !ok {
+			this.counter.read(keys[i], start, ErrCacheMiss)
+			continue
+		}
+		
+		this.counter.read(keys[i], start, nil)
+		items[keys[i]] = []byte(item)
+	}
+	
+	return items, nil
+}
+
+// setRaws - 批量写入编码后的原始缓存值 - 辅助方法
+func (this *RedisClass) setRaws(ctx context.Context, items map[string][]byte) (err error) {
+	
+	cache := this.clone()
+	if cache == nil { return fmt.Errorf("Redis 缓存未初始化") }
+	
+	start := time.Now()
+	
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+	
+	// 缓存与标签在同一个事务中写入，避免并发写入时丢失标签成员
+	write := func() error {
+		pipe := cache.pipeline()
+		for key, data := range items {
+			name := cache.Name(key)
+			pipe.Set(ctx, name, data, cache.Body.Expired)
+			for _, tag := range cache.Body.Tags {
+				pipe.SAdd(ctx, cache.tagName(tag), name)
+			}
+		}
+		// 记录原始 key，供 Scan / Export 使用
+		cache.index(ctx, pipe, cache.Body.Expired, keys...)
+		_, err := pipe.Exec(ctx)
+		return err
+	}
+	
+	// 旧版本的标签是 JSON 数组字符串，迁移后重试一次
+	if err = write(); cache.wrongType(err) {
+		if err = cache.migrateTags(ctx, cache.Body.Tags...); err == nil { err = write() }
+	}
+	
+	for key := range items { cache.counter.write(key, start, err) }
+	
+	// 重置配置
+	cache.Reset()
+	
+	return err
+}
+
+// Codec - 当前使用的编解码器
+func (this *RedisClass) Codec() CacheCodec {
+	if this.codec == nil { return CacheInst.codec("json") }
+	return this.codec
+}
+
+// Remember - 读取缓存，未命中时回源并写入
+func (this *RedisClass) Remember(key string, ttl any, fn func() (any, error)) (value any, err error) {
+	return CacheRemember[any](this, key, ttl, fn)
+}
+
+// flight - 进程内请求合并组
+func (this *RedisClass) flight() *singleflight.Group {
+	if this.group == nil { this.group = &singleflight.Group{} }
+	return this.group
+}
+
+// unlockScript - 仅当锁仍属于自己时才释放
+var unlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// lock - 获取分布式回源锁（未开启 Lock 时直接视为成功）
+func (this *RedisClass) lock(ctx context.Context, key string) (unlock func(), ok bool, err error) {
+	
+	if !this.Config.Lock { return func() {}, true, nil }
+	
+	name  := fmt.Sprintf("%s-LOCK", this.Name(key))
+	token := utils.Gen.UUID()
+	
+	ok, err = this.Client.SetNX(ctx, name, token, this.lockWait()).Result()
+	if err != nil || !ok { return func() {}, false, err }
+	
+	unlock = func() {
+		_ = unlockScript.Run(context.Background(), this.Client, []string{name}, token).Err()
+	}
+	
+	return unlock, true, nil
+}
+
+// lockWait - 分布式锁的持有与等待时间
+func (this *RedisClass) lockWait() time.Duration {
+	if this.Config.LockWait <= 0 { return 10 * time.Second }
+	return time.Duration(this.Config.LockWait) * time.Second
+}
+
+// DeleteCtx - 删除缓存（支持上下文）
+func (this *RedisClass) DeleteCtx(ctx context.Context, key ...string) (err error) {
+	
+	cache := this.clone()
+	if cache == nil { return fmt.Errorf("Redis 缓存未初始化") }
+	
+	if cloned, ok := cache.Keys(key).(*RedisClass); ok {
+		cache = cloned
+	}
+	
+	start := time.Now()
+	
+	// 根据键集删除缓存
+	err = cache.unlink(ctx, cache.Body.Keys...)
+	if err == nil && len(key) > 0 { err = cache.unindex(ctx, key...) }
+	
+	// 根据标签删除缓存
+	if tagErr := cache.delTags(ctx); err == nil { err = tagErr }
+	cache.counter.remove(key, cache.Body.Tags, start, err)
+	// 重置配置
+	cache.Reset()
+	
+	return err
+}
+
+// ClearCtx - 清空当前前缀下的缓存（支持上下文）
+/**
+ * 只删除 Body.Prefix 下的键（SCAN + 批量 UNLINK），不会影响共用同一个库的其他服务；
+ * 注意：前缀 A 会同时匹配以 "A-" 开头的其他前缀，多个应用共用一个库时请避免前缀互相包含。
+ * 需要清空整个库时请使用 FlushAll。
+ */
+func (this *RedisClass) ClearCtx(ctx context.Context) (err error) {
+	
+	if utils.Is.Empty(this.Body.Prefix) { return fmt.Errorf("缓存前缀为空，拒绝清空，请使用 FlushAll") }
+	
+	match := fmt.Sprintf("%v-*", this.Body.Prefix)
+	
+	return this.scan(ctx, match, func(client redis.Cmdable, keys []string) error {
+		return this.unlink(ctx, keys...)
+	})
+}
+
+// TTL - 获取缓存剩余存活时间
+func (this *RedisClass) TTL(key string) (ttl time.Duration, err error) {
+	
+	if utils.Is.Empty(key) { return 0, ErrCacheKeyEmpty }
+	
+	ttl, err = this.Client.PTTL(context.Background(), this.Name(key)).Result()
+	if err != nil { return 0, err }
+	
+	// -2 表示不存在，-1 表示永不过期
+	if ttl == -2 { return 0, ErrCacheMiss }
+	if ttl < 0 { return -1, nil }
+	
+	return ttl, nil
+}
+
+// Touch - 重新设置缓存的过期时间
+func (this *RedisClass) Touch(key string, ttl any) (ok bool) {
+	
+	if utils.Is.Empty(key) { return false }
+	
+	ctx  := context.Background()
+	name := this.Name(key)
+	
+	cache, _ := this.Expired(ttl).(*RedisClass)
+	if cache.Body.Expired <= 0 {
+		ok, _ = this.Client.Persist(ctx, name).Result()
+		ok = ok || this.Has(key)
+	} else {
+		ok, _ = this.Client.PExpire(ctx, name, cache.Body.Expired).Result()
+	}
+	
+	// 同步更新 key 索引中的过期时间
+	if ok {
+		pipe := this.Client.Pipeline()
+		cache.index(ctx, pipe, cache.Body.Expired, key)
+		_, _ = pipe.Exec(ctx)
+	}
+	
+	return ok
+}
+
+// GetMany - 批量获取缓存
+func (this *RedisClass) GetMany(keys []string) (values map[string]any) {
+	
+	items, err := this.getRaws(context.Background(), keys)
+	if err != nil { return map[string]any{} }
+	
+	return CacheInst.decodeMany(this.Codec(), items)
+}
+
+// SetMany - 批量设置缓存
+func (this *RedisClass) SetMany(values map[string]any) (ok bool) {
+	
+	items, err := CacheInst.encodeMany(this.Codec(), values)
+	if err != nil { return false }
+	
+	return this.setRaws(context.Background(), items) == nil
+}
+
+// DeleteByPattern - 按通配符删除缓存
+func (this *RedisClass) DeleteByPattern(pattern string) (count int, err error) {
+	
+	keys, err := this.Scan(pattern)
+	if err != nil || len(keys) == 0 { return 0, err }
+	
+	if err = this.clone().DeleteCtx(context.Background(), CacheInst.markers(keys)...); err != nil { return 0, err }
+	
+	return len(keys), nil
+}
+
+// Export - 以 JSON Lines 导出所有未过期的缓存
+func (this *RedisClass) Export(w io.Writer) (count int, err error) {
+	return CacheInst.export(w, this.Codec(), func(yield func(item cacheRecord) error) error {
+		return this.records(context.Background(), yield)
+	})
+}
+
+// Import - 导入 Export 导出的缓存
+func (this *RedisClass) Import(r io.Reader) (count int, err error) {
+	return CacheInst.load(this, r)
+}
+
+// records - 遍历 key 索引中的缓存及其标签 - 辅助方法
+func (this *RedisClass) records(ctx context.Context, yield func(item cacheRecord) error) (err error) {
+	
+	prefix := fmt.Sprintf("%v-", this.Body.Prefix)
+	
+	// 缓存名称 -> 标签
+	var mutex sync.Mutex
+	tags := make(map[string][]string)
+	
+	err = this.scan(ctx, prefix + "TAG-*", func(client redis.Cmdable, keys []string) error {
+		for _, key := range keys {
+			members, err := client.SMembers(ctx, key).Result()
+			// 旧版本的标签需要先执行 MigrateTags
+			if this.wrongType(err) { continue }
+			if err != nil { return err }
+			tag := CacheInst.exportTag(strings.TrimPrefix(key, prefix))
+			mutex.Lock()
+			for _, name := range members {
+				tags[name] = append(tags[name], tag)
+			}
+			mutex.Unlock()
+		}
+		return nil
+	})
+	if err != nil { return err }
+	
+	return this.indexed(ctx, func(names, keys []string) error {
+		
+		pipe   := this.Client.Pipeline()
+		values := make([]*redis.StringCmd, len(names))
+		ttls   := make([]*redis.DurationCmd, len(names))
+		for i, name := range names {
+			values[i] = pipe.Get(ctx, name)
+			ttls[i]   = pipe.PTTL(ctx, name)
+		}
+		if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) { return err }
+		
+		for i := range values {
+			
+			data, err := values[i].Bytes()
+			if err != nil { continue }
+			
+			ttl := ttls[i].Val()
+			if ttl == -2 { continue }
+			
+			item := cacheRecord{ key: keys[i], data: data, tags: tags[names[i]] }
+			if ttl > 0 { item.ttl = ttl }
+			
+			if err = yield(item); err != nil { return err }
+		}
+		
+		return nil
+	})
+}
+
+// Incr - 原子自增
+func (this *RedisClass) Incr(key string, delta int64) (value int64, err error) {
+	return this.IncrWithTTL(key, delta, nil)
+}
+
+// Decr - 原子自减
+func (this *RedisClass) Decr(key string, delta int64) (value int64, err error) {
+	return this.IncrWithTTL(key, -delta, nil)
+}
+
+// incrScript - 自增，仅在首次创建时设置过期时间
+var incrScript = redis.NewScript(`
+local created = redis.call("EXISTS", KEYS[1]) == 0
+local value = redis.call("INCRBY", KEYS[1], ARGV[1])
+if created and tonumber(ARGV[2]) > 0 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+if created then return {value, 1} end
+return {value, 0}
+`)
+
+// IncrWithTTL - 原子自增，仅在首次创建时设置过期时间
+func (this *RedisClass) IncrWithTTL(key string, delta int64, ttl any) (value int64, err error) {
+	
+	cache := this.clone()
+	if cache == nil { return 0, fmt.Errorf("Redis 缓存未初始化") }
+	if utils.Is.Empty(key) { return 0, ErrCacheKeyEmpty }
+	
+	if ttl != nil { cache, _ = cache.Expired(ttl).(*RedisClass) }
+	// 重置配置
+	defer cache.Reset()
+	
+	ctx  := context.Background()
+	name := cache.Name(key)
+	
+	result, err := incrScript.Run(ctx, cache.Client, []string{name}, delta, cache.Body.Expired.Milliseconds()).Int64Slice()
+	if err != nil { return 0, err }
+	
+	value = result[0]
+	
+	// 首次创建时补充 key 索引和标签
+	if result[1] == 1 {
+		pipe := cache.Client.Pipeline()
+		cache.index(ctx, pipe, cache.Body.Expired, key)
+		if _, err = pipe.Exec(ctx); err != nil { return value, err }
+		if err = cache.setTags(ctx, key); err != nil { return value, err }
+	}
+	
+	return value, nil
+}
+
+// Scan - 按通配符列出缓存的原始 key
+/**
+ * 基于写入时记录的 key 索引（<Prefix>-KEYS-<分片>），遍历时会顺带清理已不存在的索引项。
+ */
+func (this *RedisClass) Scan(pattern string) (keys []string, err error) {
 	
 	ctx := context.Background()
-	err := this.Client.FlushDB(ctx).Err()
-	return utils.Ternary[bool](err != nil, false, true)
+	
+	err = this.indexed(ctx, func(names, originals []string) error {
+		
+		var matched []int
+		for i, key := range originals {
+			if CacheInst.match(pattern, key) { matched = append(matched, i) }
+		}
+		if len(matched) == 0 { return nil }
+		
+		pipe   := this.Client.Pipeline()
+		exists := make([]*redis.IntCmd, len(matched))
+		for i, index := range matched {
+			exists[i] = pipe.Exists(ctx, names[index])
+		}
+		if _, err := pipe.Exec(ctx); err != nil { return err }
+		
+		var stale []string
+		for i, item := range exists {
+			if item.Val() == 1 {
+				keys = append(keys, originals[matched[i]])
+			} else {
+				stale = append(stale, originals[matched[i]])
+			}
+		}
+		
+		// 通过标签删除的缓存不会同步清理索引，在这里补充清理
+		if len(stale) > 0 { _ = this.unindex(ctx, stale...) }
+		
+		return nil
+	})
+	
+	return keys, err
+}
+
+// cacheStatsSample - Stats 估算占用字节数时每个索引分片的采样条数
+const cacheStatsSample = 32
+
+// Stats - 缓存统计
+/**
+ * 缓存条数按 key 索引统计；占用字节数按采样的平均大小估算，避免逐个 key 执行 STRLEN。
+ */
+func (this *RedisClass) Stats() (stats CacheStats) {
+	
+	ctx   := context.Background()
+	stats  = this.counter.stats("redis")
+	
+	now   := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	names := this.indexNames()
+	
+	pipe   := this.Client.Pipeline()
+	counts := make([]*redis.IntCmd, len(names))
+	ranges := make([]*redis.StringSliceCmd, len(names))
+	for i, name := range names {
+		counts[i] = pipe.ZCount(ctx, name, "(" + now, "+inf")
+		ranges[i] = pipe.ZRangeByScore(ctx, name, &redis.ZRangeBy{Min: "(" + now, Max: "+inf", Count: cacheStatsSample})
+	}
+	if _, err := pipe.Exec(ctx); err != nil { return stats }
+	
+	pipe = this.Client.Pipeline()
+	var sizes []*redis.IntCmd
+	for i := range names {
+		stats.Keys += counts[i].Val()
+		for _, key := range ranges[i].Val() {
+			sizes = append(sizes, pipe.StrLen(ctx, this.Name(key)))
+		}
+	}
+	if len(sizes) == 0 { return stats }
+	if _, err := pipe.Exec(ctx); err != nil { return stats }
+	
+	var total int64
+	for _, item := range sizes {
+		total += item.Val()
+	}
+	stats.Size = total * stats.Keys / int64(len(sizes))
+	
+	return stats
+}
+
+// internal - 是否为内部使用的键（标签、索引、锁） - 辅助方法
+func (this *RedisClass) internal(name string) bool {
+	
+	if strings.HasPrefix(name, fmt.Sprintf("%v-TAG-", this.Body.Prefix)) { return true }
+	if strings.HasPrefix(name, fmt.Sprintf("%v-KEYS-", this.Body.Prefix)) { return true }
+	
+	return strings.HasSuffix(name, "-LOCK")
+}
+
+// FlushAll - 清空当前 Redis 库中的所有键（包括其他服务的键）
+func (this *RedisClass) FlushAll() (ok bool) {
+	return this.FlushAllCtx(context.Background()) == nil
+}
+
+// FlushAllCtx - 清空当前 Redis 库中的所有键（支持上下文）
+func (this *RedisClass) FlushAllCtx(ctx context.Context) (err error) {
+	
+	if item, ok := this.Client.(*redis.ClusterClient); ok {
+		return item.ForEachMaster(ctx, func(ctx context.Context, client *redis.Client) error {
+			return client.FlushDB(ctx).Err()
+		})
+	}
+	
+	return this.Client.FlushDB(ctx).Err()
+}
+
+// Name - 缓存名称规则
+func (this *RedisClass) Name(key any) string {
+	return fmt.Sprintf("%s-%s", this.Body.Prefix, utils.Hash.Sum32(key))
+	// return utils.Hash.Sum32(fmt.Sprintf("%vkey=%v", this.Body.Prefix, key))
+}
+
+// Reset - 重置配置 - 辅助方法
+func (this *RedisClass) Reset() {
+	this.Body.Keys    = []string{}
+	this.Body.Tags    = []string{}
+	this.Body.Expired = time.Duration(this.Config.Expired) * time.Second
+}
+
+// SetTags - 设置标签 - 辅助方法
+func (this *RedisClass) SetTags(key string) {
+	_ = this.setTags(context.Background(), key)
+}
+
+// DelTags - 删除标签
+func (this *RedisClass) DelTags() {
+	_ = this.delTags(context.Background())
+}
+
+// setTags - 设置标签（支持上下文） - 辅助方法
+func (this *RedisClass) setTags(ctx context.Context, key string) (err error) {
+	
+	if len(this.Body.Tags) == 0 { return nil }
+	
+	write := func() error {
+		pipe := this.pipeline()
+		for _, tag := range this.Body.Tags {
+			pipe.SAdd(ctx, this.tagName(tag), this.Name(key))
+		}
+		_, err := pipe.Exec(ctx)
+		return err
+	}
+	
+	if err = write(); this.wrongType(err) {
+		if err = this.migrateTags(ctx, this.Body.Tags...); err == nil { err = write() }
+	}
+	
+	return err
+}
+
+// delTagsScript - 原子删除标签及其下所有成员
+/**
+ * 标签中记录的是缓存名称，key 索引中的对应项由 Scan 或过期清理时移除。
+ */
+var delTagsScript = redis.NewScript(`
+for i = 1, #KEYS do
+	local members = redis.call("SMEMBERS", KEYS[i])
+	for j = 1, #members, 500 do
+		local batch = {unpack(members, j, math.min(j + 499, #members))}
+		redis.call("UNLINK", unpack(batch))
+	end
+	redis.call("DEL", KEYS[i])
+end
+return #KEYS
+`)
+
+// delTags - 删除标签（支持上下文） - 辅助方法
+func (this *RedisClass) delTags(ctx context.Context) (err error) {
+	
+	if len(this.Body.Tags) == 0 { return nil }
+	
+	// 集群模式下脚本不能跨 slot 操作，逐个标签读取成员后删除（非原子）
+	if this.cluster() {
+		for _, tag := range this.Body.Tags {
+			members, err := this.tagMembers(ctx, tag)
+			if err != nil { return err }
+			if err = this.unlink(ctx, append(members, this.tagName(tag))...); err != nil { return err }
+		}
+		return nil
+	}
+	
+	var names []string
+	for _, tag := range this.Body.Tags {
+		names = append(names, this.tagName(tag))
+	}
+	
+	if err = delTagsScript.Run(ctx, this.Client, names).Err(); this.wrongType(err) {
+		if err = this.migrateTags(ctx, this.Body.Tags...); err == nil {
+			err = delTagsScript.Run(ctx, this.Client, names).Err()
+		}
+	}
+	
+	return err
+}
+
+// cacheIndexShards - key 索引的分片数（集群模式下分散到不同 slot，避免每次写入都落在同一个热点 key 上）
+const cacheIndexShards = 16
+
+// indexName - 原始 key 所在的索引分片 - 辅助方法
+/**
+ * 索引为有序集合：成员为原始 key（缓存名称可由 Name 计算），分值为过期时间的毫秒时间戳，永不过期为 +inf。
+ */
+func (this *RedisClass) indexName(key string) string {
+	
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(key))
+	
+	return fmt.Sprintf("%v-KEYS-%d", this.Body.Prefix, hash.Sum32() % cacheIndexShards)
+}
+
+// indexNames - 所有索引分片 - 辅助方法
+func (this *RedisClass) indexNames() (names []string) {
+	for i := 0; i < cacheIndexShards; i++ {
+		names = append(names, fmt.Sprintf("%v-KEYS-%d", this.Body.Prefix, i))
+	}
+	return names
+}
+
+// index - 在管道中记录原始 key，并清理所在分片中已过期的索引项 - 辅助方法
+func (this *RedisClass) index(ctx context.Context, pipe redis.Pipeliner, expired time.Duration, keys ...string) {
+	
+	score := math.Inf(1)
+	if expired > 0 { score = float64(time.Now().Add(expired).UnixMilli()) }
+	
+	now    := "(" + strconv.FormatInt(time.Now().UnixMilli(), 10)
+	shards := make(map[string]bool)
+	
+	for _, key := range keys {
+		name := this.indexName(key)
+		pipe.ZAdd(ctx, name, redis.Z{Score: score, Member: key})
+		shards[name] = true
+	}
+	
+	for name := range shards {
+		pipe.ZRemRangeByScore(ctx, name, "-inf", now)
+	}
+}
+
+// unindex - 从索引中移除原始 key - 辅助方法
+func (this *RedisClass) unindex(ctx context.Context, keys ...string) (err error) {
+	
+	pipe := this.Client.Pipeline()
+	for _, key := range keys {
+		pipe.ZRem(ctx, this.indexName(key), key)
+	}
+	_, err = pipe.Exec(ctx)
+	
+	return err
+}
+
+// indexed - 分批遍历索引中未过期的原始 key - 辅助方法
+/**
+ * @param fn 每批的缓存名称与对应的原始 key
+ */
+func (this *RedisClass) indexed(ctx context.Context, fn func(names, keys []string) error) (err error) {
+	
+	now := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	
+	for _, index := range this.indexNames() {
+		
+		// 先清理已过期的索引项
+		if err = this.Client.ZRemRangeByScore(ctx, index, "-inf", "(" + now).Err(); err != nil { return err }
+		
+		var cursor uint64
+		
+		for {
+			
+			items, next, err := this.Client.ZScan(ctx, index, cursor, "", 500).Result()
+			if err != nil { return err }
+			
+			// ZSCAN 返回 member、score 交替的列表
+			names := make([]string, 0, len(items) / 2)
+			keys  := make([]string, 0, len(items) / 2)
+			for i := 0; i+1 < len(items); i += 2 {
+				names = append(names, this.Name(items[i]))
+				keys  = append(keys, items[i])
+			}
+			
+			if len(keys) > 0 {
+				if err = fn(names, keys); err != nil { return err }
+			}
+			
+			if cursor = next; cursor == 0 { break }
+		}
+	}
+	
+	return nil
+}
+
+// tagName - 标签的存储名称 - 辅助方法
+func (this *RedisClass) tagName(tag string) string {
+	return fmt.Sprintf("%v-%v", this.Body.Prefix, tag)
+}
+
+// tagMembers - 获取标签下的所有缓存名称 - 辅助方法
+func (this *RedisClass) tagMembers(ctx context.Context, tag string) (names []string, err error) {
+	
+	names, err = this.Client.SMembers(ctx, this.tagName(tag)).Result()
+	if this.wrongType(err) {
+		if err = this.migrateTags(ctx, tag); err == nil {
+			names, err = this.Client.SMembers(ctx, this.tagName(tag)).Result()
+		}
+	}
+	
+	return names, err
+}
+
+// wrongType - 是否为类型错误（旧版本的标签使用字符串存储） - 辅助方法
+func (this *RedisClass) wrongType(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "WRONGTYPE")
+}
+
+// migrateTagScript - 把 JSON 数组字符串转换为集合（值被并发修改时放弃本次转换）
+var migrateTagScript = redis.NewScript(`
+if redis.call("TYPE", KEYS[1]).ok ~= "string" then return 0 end
+if redis.call("GET", KEYS[1]) ~= ARGV[1] then return -1 end
+redis.call("DEL", KEYS[1])
+for i = 2, #ARGV do
+	redis.call("SADD", KEYS[1], ARGV[i])
+end
+return #ARGV - 1
+`)
+
+// MigrateTags - 把旧版本以 JSON 数组字符串存储的标签迁移为 Redis 集合
+/**
+ * 写入或删除标签时遇到旧格式会自动迁移，此方法用于一次性批量迁移。
+ * @param ctx 上下文
+ * @return int 迁移的标签数量, error
+ */
+func (this *RedisClass) MigrateTags(ctx context.Context) (count int, err error) {
+	
+	var mutex sync.Mutex
+	match := fmt.Sprintf("%v-TAG-*", this.Body.Prefix)
+	
+	err = this.scan(ctx, match, func(client redis.Cmdable, keys []string) error {
+		
+		for _, name := range keys {
+			
+			kind, err := client.Type(ctx, name).Result()
+			if err != nil { return err }
+			if kind != "string" { continue }
+			
+			if err = this.migrateTag(ctx, name); err != nil { return err }
+			
+			mutex.Lock()
+			count++
+			mutex.Unlock()
+		}
+		
+		return nil
+	})
+	
+	return count, err
+}
+
+// migrateTags - 迁移指定标签 - 辅助方法
+func (this *RedisClass) migrateTags(ctx context.Context, tags ...string) (err error) {
+	
+	for _, tag := range tags {
+		if err = this.migrateTag(ctx, this.tagName(tag)); err != nil { return err }
+	}
+	
+	return nil
+}
+
+// migrateTag - 迁移单个标签（name 为完整的存储名称） - 辅助方法
+func (this *RedisClass) migrateTag(ctx context.Context, name string) (err error) {
+	
+	// 并发修改时重试几次
+	for range 3 {
+		
+		read, err := this.Client.Get(ctx, name).Result()
+		if errors.Is(err, redis.Nil) || this.wrongType(err) { return nil }
+		if err != nil { return err }
+		
+		args := []any{read}
+		for _, member := range cast.ToStringSlice(utils.Json.Decode(read)) {
+			args = append(args, member)
+		}
+		
+		result, err := migrateTagScript.Run(ctx, this.Client, []string{name}, args...).Int()
+		if err != nil { return err }
+		if result >= 0 { return nil }
+	}
+	
+	return fmt.Errorf("迁移标签 %s 失败：数据被并发修改", name)
+}
+
+// ============================ 文件缓存 ============================
+
+// FileClass - 文件缓存实现
+type FileClass struct {
+	// 文件客户端
+	Fs     afero.Fs
+	// 缓存参数
+	Body   CacheBody
+	// 当前配置
+	Config dto.CacheFileConfig
+	// 存储目录
+	Root   string
+	// 文件后缀
+	Suffix string
+	// 编解码器
+	codec  CacheCodec
+	// 请求合并组
+	group  *singleflight.Group
+	// 命中计数器
+	counter *cacheCounter
+	// 后台清理（同一实例的所有克隆共享）
+	janitor *fileJanitor
+}
+
+// fileJanitor - 文件缓存后台清理
+type fileJanitor struct {
+	// 停止信号
+	stop chan struct{}
+	// 保证只关闭一次
+	once sync.Once
+}
+
+// clone - 克隆文件缓存实例（共享文件系统对象，隔离链式上下文）
+func (this *FileClass) clone() *FileClass {
+	
+	if this == nil { return nil }
+
+	clone := *this
+	clone.Body = CacheInst.cloneBody(this.Body)
+	return &clone
+}
+
+// NewCache - 按配置创建新的缓存实例
+func (this *FileClass) NewCache(config dto.CacheConfig) CacheAPI {
+	return CacheInst.newWithConfig(config)
+}
+
+// FileCacheResp - 文件缓存结构
+type FileCacheResp struct {
+	// 过期时间戳
+	Expired int64 `json:"expired"`
+	// 原始 key（供 Scan 使用）
+	Key string `json:"key,omitempty"`
+	// 编解码器（为空或 json 时 value 为原始 JSON，其余为 base64 字节）
+	Codec string `json:"codec,omitempty"`
+	// 缓存值
+	Value any `json:"value"`
+}
+
+// fileCacheRow - 文件缓存读取结构（保留 value 的原始形态）
+type fileCacheRow struct {
+	// 过期时间戳
+	Expired int64           `json:"expired"`
+	// 原始 key
+	Key     string          `json:"key,omitempty"`
+	// 编解码器
+	Codec   string          `json:"codec,omitempty"`
+	// 缓存值
+	Value   JSON.RawMessage `json:"value"`
+}
+
+// bytes - 取出编码后的缓存值
+func (this *fileCacheRow) bytes() (data []byte, err error) {
+	
+	// 只有内嵌的 JSON 值没有 codec 标记，其余（包括压缩、加密后的 JSON）都是 base64 字节
+	if this.Codec == "" { return this.Value, nil }
+	
+	err = JSON.Unmarshal(this.Value, &data)
+	return data, err
+}
+
+// Init 初始化 文件缓存
+func (this *FileClass) Init(config dto.CacheFileConfig) {
+	
+	this.Config = config
+	
+	this.Root   = this.Config.Root
+	// 设置文件后缀
+	this.Suffix = this.Config.Suffix
+	
+	prefix := this.Config.Prefix
+	if !utils.Is.Empty(prefix) {
+		this.Body.Prefix = fmt.Sprintf("%v", prefix)
+	}
+	
+	this.Fs = afero.NewOsFs()
+	this.Body.Expired = time.Duration(this.Config.Expired) * time.Second
+	this.group   = &singleflight.Group{}
+	this.counter = &cacheCounter{engine: "file"}
+	
+	if this.Config.Sweep > 0 {
+		this.janitor = &fileJanitor{stop: make(chan struct{})}
+		go this.sweeper(this.janitor, time.Duration(this.Config.Sweep) * time.Second)
+	}
+}
+
+// Close - 停止后台清理
+func (this *FileClass) Close() {
+	
+	if this == nil || this.janitor == nil { return }
+	
+	this.janitor.once.Do(func() { close(this.janitor.stop) })
+}
+
+// sweeper - 定时清理过期缓存
+func (this *FileClass) sweeper(janitor *fileJanitor, interval time.Duration) {
+	
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	
+	for {
+		select {
+		case <-janitor.stop:
+			return
+		case <-ticker.C:
+			_, _ = this.Sweep()
+		}
+	}
+}
+
+// fileTempStale - 临时文件超过该时长仍未重命名，视为写入中途崩溃遗留的文件
+const fileTempStale = 10 * time.Minute
+
+// Sweep - 立即清理所有过期缓存，以及写入中途崩溃遗留的临时文件
+/**
+ * @return int 删除的过期缓存文件数, error
+ */
+func (this *FileClass) Sweep() (count int, err error) {
+	
+	now := time.Now().Unix()
+	
+	var expired []string
+	err = this.walk(func(dest string, row *fileCacheRow, size int64) {
+		if row.Expired < now { expired = append(expired, dest) }
+	})
+	if err != nil { return 0, err }
+	
+	this.sweepTemp()
+	
+	if len(expired) == 0 { return 0, nil }
+	
+	return this.purge(expired...)
+}
+
+// sweepTemp - 删除遗留的临时文件 - 辅助方法
+func (this *FileClass) sweepTemp() {
+	
+	if !this.Exist(this.Root) { return }
+	
+	deadline := time.Now().Add(-fileTempStale)
+	
+	_ = afero.Walk(this.Fs, this.Root, func(dest string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() { return nil }
+		if strings.HasPrefix(info.Name(), ".tmp-") && info.ModTime().Before(deadline) {
+			_ = this.Fs.Remove(dest)
+		}
+		return nil
+	})
+}
+
+// purge - 在文件锁内删除仍然过期的缓存文件 - 辅助方法
+func (this *FileClass) purge(dests ...string) (count int, err error) {
+	
+	for _, dest := range dests {
+		
+		unlock, err := this.klock(filepath.Base(dest))
+		if err != nil { return count, err }
+		
+		// 持锁后再确认一次，文件可能刚被其他进程重新写入
+		row, err := this.read(dest)
+		if err == nil && row.Expired < time.Now().Unix() && this.DeleteFile(dest) == nil {
+			count++
+			this.counter.emit(CacheEventExpire, "expire", row.Key, nil, time.Time{}, nil)
+		}
+		
+		unlock()
+	}
+	
+	return count, nil
+}
+
+// Key - 键
+func (this *FileClass) Key(key ...string) CacheAPI {
+	cache := this.clone()
+	var keys []string
+	// 把多个标签转换为数组
+	for _, value := range key {
+		keys = append(keys, cache.Name(value))
+	}
+	// 合并之前的标签
+	if len(cache.Body.Keys) > 0 {
+		keys = append(keys, cache.Body.Keys...)
+	}
+	// 去重
+	cache.Body.Keys = cast.ToStringSlice(utils.ArrayUnique(keys))
+	return cache
+}
+
+// Keys - 键集
+func (this *FileClass) Keys(key []string) CacheAPI {
+	cache := this.clone()
+	var keys []string
+	// 把多个标签转换为数组
+	for _, value := range key {
+		keys = append(keys, cache.Name(value))
+	}
+	// 合并之前的标签
+	if len(cache.Body.Keys) > 0 {
+		keys = append(keys, cache.Body.Keys...)
+	}
+	// 去重
+	cache.Body.Keys = cast.ToStringSlice(utils.ArrayUnique(keys))
+	return cache
+}
+
+// Tag - 标签
+func (this *FileClass) Tag(tag ...string) CacheAPI {
+	cache := this.clone()
+	var tags []string
+	// 把多个标签转换为数组
+	for _, value := range tag {
+		tags = append(tags, strings.ToUpper(fmt.Sprintf("tag-%v", value)))
+	}
+	// 合并之前的标签
+	if len(cache.Body.Tags) > 0 {
+		tags = append(tags, cache.Body.Tags...)
+	}
+	// 去重
+	cache.Body.Tags = cast.ToStringSlice(utils.ArrayUnique(tags))
+	return cache
 }
 
-// Name - 缓存名称规则
-func (this *RedisClass) Name(key any) string {
-	return fmt.Sprintf("%s-%s", this.Body.Prefix, utils.Hash.Sum32(key))
-	// return utils.Hash.Sum32(fmt.Sprintf("%vkey=%v", this.Body.Prefix, key))
+// Tags - 标签
+func (this *FileClass) Tags(tag []string) CacheAPI {
+	cache := this.clone()
+	var tags []string
+	// 把多个标签转换为数组
+	for _, value := range tag {
+		tags = append(tags, strings.ToUpper(fmt.Sprintf("tag-%v", value)))
+	}
+	// 合并之前的标签
+	if len(cache.Body.Tags) > 0 {
+		tags = append(tags, cache.Body.Tags...)
+	}
+	// 去重
+	cache.Body.Tags = cast.ToStringSlice(utils.ArrayUnique(tags))
+	return cache
 }
 
-// Reset - 重置配置 - 辅助方法
-func (this *RedisClass) Reset() {
-	this.Body.Keys    = []string{}
-	this.Body.Tags    = []string{}
-	this.Body.Expired = time.Duration(this.Config.Expired) * time.Second
+// SetTags - 设置标签
+func (this *FileClass) SetTags(key string) {
+	
+	unlock, err := this.flock()
+	if err != nil { return }
+	defer unlock()
+	
+	this.setTags(key)
 }
 
-// SetTags - 设置标签 - 辅助方法
-func (this *RedisClass) SetTags(key string) {
+// DelTags - 删除标签
+func (this *FileClass) DelTags() {
 	
-	ctx := context.Background()
+	unlock, err := this.flock()
+	if err != nil { return }
+	defer unlock()
+	
+	this.delTags()
+}
+
+// setTags - 把缓存加入标签索引（调用方需持有标签索引锁）
+func (this *FileClass) setTags(key ...string) {
+	
+	if len(key) == 0 { return }
+	
+	names := make([]string, len(key))
+	for i, value := range key {
+		names[i] = this.Name(value)
+	}
 	
 	for _, tag := range this.Body.Tags {
 		
-		var keys []string
+		var value []byte
+		var keys  []string
 		
-		// 文件名
-		name := fmt.Sprintf("%v-%v", this.Body.Prefix, tag)
+		// 存储路径
+		dest := this.tagDest(tag)
 		
 		// 不存在标签
-		if !this.Has(name) {
+		if !this.Exist(dest) {
 			// 添加新成员
-			keys = append(keys, this.Name(key))
-			// 设置缓存
-			_ = this.Client.Set(ctx, name, utils.Json.Encode(keys), 0).Err()
+			keys = append(keys, names...)
+			value = []byte(utils.Json.Encode(keys))
+			_ = this.Write(dest, value)
 			continue
 		}
 		
 		// 获取标签下的所有成员
-		read, _ := this.Client.Get(ctx, name).Result()
-		keys = cast.ToStringSlice(utils.Json.Decode(read))
+		read, _ := this.Read(dest)
+		keys  = cast.ToStringSlice(utils.Json.Decode(read))
 		
 		// 添加新成员
-		keys = append(keys, this.Name(key))
+		keys  = append(keys, names...)
 		// 去重
-		keys = cast.ToStringSlice(utils.ArrayUnique(keys))
+		keys  = cast.ToStringSlice(utils.ArrayUnique(keys))
+		// 重新设置
+		value = []byte(utils.Json.Encode(keys))
 		// 写入文件
-		_ = this.Client.Set(ctx, name, utils.Json.Encode(keys), 0).Err()
+		_ = this.Write(dest, value)
 	}
 }
 
-// DelTags - 删除标签
-func (this *RedisClass) DelTags() {
-	
-	ctx := context.Background()
-	
+// delTags - 删除标签及其下所有缓存（调用方需持有标签索引锁）
+func (this *FileClass) delTags() {
 	for _, tag := range this.Body.Tags {
 		
-		// 文件名
-		name := fmt.Sprintf("%v-%v", this.Body.Prefix, tag)
+		// 存储路径
+		dest := this.tagDest(tag)
 		
-		ok, _ := this.Client.Exists(ctx, name).Result()
 		// 不存在标签
-		if ok != 1 {
+		if !this.Exist(dest) {
 			continue
 		}
 		
 		// 获取标签下的所有成员
-		read, _ := this.Client.Get(ctx, name).Result()
+		read, _ := this.Read(dest)
 		keys := cast.ToStringSlice(utils.Json.Decode(read))
 		// 删除标签下的所有成员
-		_ = this.Client.Del(ctx, keys...).Err()
+		for _, key := range keys {
+			_ = this.DeleteFile(this.path(key))
+		}
 		// 删除标签文件
-		_ = this.Client.Del(ctx, name).Err()
+		_ = this.DeleteFile(dest)
 	}
 }
 
-// ============================ 文件缓存 ============================
+// tagDest - 标签索引文件路径（标签文件不分目录，固定在根目录） - 辅助方法
+func (this *FileClass) tagDest(tag string) string {
+	return fmt.Sprintf("%s/%v-%v.%s", this.Root, this.Body.Prefix, tag, this.Suffix)
+}
 
-// FileClass - 文件缓存实现
-type FileClass struct {
-	// 文件客户端
-	Fs     afero.Fs
-	// 缓存参数
-	Body   CacheBody
-	// 当前配置
-	Config dto.CacheFileConfig
-	// 存储目录
-	Root   string
-	// 文件后缀
-	Suffix string
+// Expired - 过期时间
+func (this *FileClass) Expired(second any) CacheAPI {
+	cache := this.clone()
+	
+	// 判断 second 是否为 time.Duration 类型或可解析为 duration 字符串
+	switch v := second.(type) {
+	case time.Duration:
+		cache.Body.Expired = v
+	case string:
+		// 尝试解析为 duration 字符串（例如 `5s`, `1m`）
+		if d, err := time.ParseDuration(v); err == nil {
+			cache.Body.Expired = d
+		} else if i := cast.ToInt64(v); i > 0 {
+			cache.Body.Expired = time.Duration(i) * time.Second
+		} else {
+			cache.Body.Expired = cast.ToDuration(v)
+		}
+	default:
+		// 对于数值类型，按秒处理；对于其他类型，尽量转换为 duration
+		if cast.ToInt64(second) > 0 {
+			cache.Body.Expired = time.Duration(cast.ToInt64(second)) * time.Second
+		} else {
+			// 一百年
+			cache.Body.Expired = cast.ToDuration(100*365*24*60*60) * time.Second
+		}
+	}
+	
+	return cache
 }
 
-// clone - 克隆文件缓存实例（共享文件系统对象，隔离链式上下文）
-func (this *FileClass) clone() *FileClass {
+// Stale - 允许过期后继续返回旧值的时长（0 表示关闭）
+func (this *FileClass) Stale(second any) CacheAPI {
 	
-	if this == nil { return nil }
+	cache := this.clone()
+	// 不能复用 Expired：文件缓存的 Expired 会把 0 转换为一百年
+	cache.Body.Stale = CacheInst.duration(second)
+	
+	return cache
+}
 
-	clone := *this
-	clone.Body = CacheInst.cloneBody(this.Body)
-	return &clone
+// Negative - 空结果的缓存时长（0 表示关闭）
+func (this *FileClass) Negative(second any) CacheAPI {
+	
+	cache := this.clone()
+	cache.Body.Negative = CacheInst.duration(second)
+	
+	return cache
 }
 
-// NewCache - 按配置创建新的缓存实例
-func (this *FileClass) NewCache(config dto.CacheConfig) CacheAPI {
+// body - 当前链式参数
+func (this *FileClass) body() CacheBody { return this.Body }
+
+// Has - 判断缓存是否存在
+func (this *FileClass) Has(key string) (ok bool) {
+	ok, _ = this.HasCtx(context.Background(), key)
+	return ok
+}
+
+// Get - 获取缓存
+func (this *FileClass) Get(key string) (value any) {
+	value, _ = this.GetCtx(context.Background(), key)
+	return value
+}
+
+// Set - 设置缓存
+func (this *FileClass) Set(key string, value any) (ok bool) {
+	return this.SetCtx(context.Background(), key, value) == nil
+}
+
+// Delete - 删除缓存
+func (this *FileClass) Delete(key ...string) (ok bool) {
+	return this.DeleteCtx(context.Background(), key...) == nil
+}
+
+// Clear - 清空缓存
+func (this *FileClass) Clear() (ok bool) {
+	return this.ClearCtx(context.Background()) == nil
+}
+
+// HasCtx - 判断缓存是否存在（支持上下文）
+func (this *FileClass) HasCtx(ctx context.Context, key string) (ok bool, err error) {
 	
-	conf := CacheInst.normConfig(config)
+	if utils.Is.Empty(key) { return false, ErrCacheKeyEmpty }
+	if err = ctx.Err(); err != nil { return false, err }
 	
-	switch strings.ToLower(conf.Engine) {
-	case "redis":
-		cache := &RedisClass{}
-		cache.Init(conf.Redis)
-		return cache
-	default:
-		cache := &FileClass{}
-		cache.Init(conf.File)
-		return cache
+	// 与 Redis、内存引擎一致，Has 不计入命中率统计
+	_, err = this.row(key, true)
+	if errors.Is(err, ErrCacheMiss) { return false, nil }
+	if err != nil { return false, err }
+	
+	return true, nil
+}
+
+// GetCtx - 获取缓存（支持上下文）
+func (this *FileClass) GetCtx(ctx context.Context, key string) (value any, err error) {
+	
+	data, err := this.GetRaw(ctx, key)
+	if err != nil { return nil, err }
+	
+	if err = CacheInst.decode(this.Codec(), key, data, &value); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCacheDecode, err)
 	}
+	
+	// 返回缓存值
+	return value, nil
 }
 
-// FileCacheResp - 文件缓存结构
-type FileCacheResp struct {
-	// 过期时间戳
-	Expired int64 `json:"expired"`
-	// 缓存值
-	Value any `json:"value"`
+// SetCtx - 设置缓存（支持上下文）
+func (this *FileClass) SetCtx(ctx context.Context, key string, value any) (err error) {
+	
+	data, err := CacheInst.encode(this.Codec(), key, value)
+	if err != nil { return fmt.Errorf("编码缓存失败: %w", err) }
+	
+	return this.SetRaw(ctx, key, data)
 }
 
-// Init 初始化 文件缓存
-func (this *FileClass) Init(config dto.CacheFileConfig) {
+// GetRaw - 获取编码后的原始缓存值
+func (this *FileClass) GetRaw(ctx context.Context, key string) (data []byte, err error) {
 	
-	this.Config = config
+	if utils.Is.Empty(key) { return nil, ErrCacheKeyEmpty }
+	if err = ctx.Err(); err != nil { return nil, err }
 	
-	this.Root   = this.Config.Root
-	// 设置文件后缀
-	this.Suffix = this.Config.Suffix
+	start := time.Now()
+	row, err := this.row(key, true)
+	this.counter.read(key, start, err)
+	if err != nil { return nil, err }
 	
-	prefix := this.Config.Prefix
-	if !utils.Is.Empty(prefix) {
-		this.Body.Prefix = fmt.Sprintf("%v", prefix)
+	return row.bytes()
+}
+
+// row - 读取未过期的缓存记录 - 辅助方法
+/**
+ * @param purge 记录过期时是否删除文件（已持有文件锁的调用方必须传 false，否则会死锁）
+ */
+func (this *FileClass) row(key string, purge bool) (row *fileCacheRow, err error) {
+	
+	dest := this.Dest(key)
+	
+	row, err = this.read(dest)
+	if err != nil { return nil, err }
+	
+	// 检查缓存是否过期
+	if row.Expired < time.Now().Unix() {
+		// 删除过期缓存
+		if purge { _, _ = this.purge(dest) }
+		return nil, ErrCacheMiss
 	}
 	
-	this.Fs = afero.NewOsFs()
-	this.Body.Expired = time.Duration(this.Config.Expired) * time.Second
+	return row, nil
 }
 
-// Key - 键
-func (this *FileClass) Key(key ...string) CacheAPI {
-	cache := this.clone()
-	var keys []string
-	// 把多个标签转换为数组
-	for _, value := range key {
-		keys = append(keys, cache.Name(value))
-	}
-	// 合并之前的标签
-	if len(cache.Body.Keys) > 0 {
-		keys = append(keys, cache.Body.Keys...)
+// read - 读取并解析缓存文件 - 辅助方法
+func (this *FileClass) read(dest string) (row *fileCacheRow, err error) {
+	
+	// 检查缓存是否存在
+	if !this.Exist(dest) { return nil, ErrCacheMiss }
+	
+	// 读取文件内容
+	content, err := this.Read(dest)
+	if err != nil { return nil, err }
+	
+	row = &fileCacheRow{}
+	
+	if err = utils.Json.Unmarshal(content, row); err != nil {
+		return nil, fmt.Errorf("解析缓存文件失败: %w", err)
 	}
-	// 去重
-	cache.Body.Keys = cast.ToStringSlice(utils.ArrayUnique(keys))
-	return cache
+	
+	return row, nil
 }
 
-// Keys - 键集
-func (this *FileClass) Keys(key []string) CacheAPI {
+// SetRaw - 写入编码后的原始缓存值
+func (this *FileClass) SetRaw(ctx context.Context, key string, data []byte) (err error) {
+	
 	cache := this.clone()
-	var keys []string
-	// 把多个标签转换为数组
-	for _, value := range key {
-		keys = append(keys, cache.Name(value))
+	if cache == nil { return fmt.Errorf("文件缓存未初始化") }
+	if utils.Is.Empty(key) { return ErrCacheKeyEmpty }
+	if err = ctx.Err(); err != nil { return err }
+	
+	start := time.Now()
+	
+	// 缓存文件只锁所在分片，不同 key 的写入可以并行
+	unlock, err := cache.klock(cache.Name(key))
+	if err != nil { return err }
+	
+	err = cache.Write(cache.Dest(key), cache.record(key, data))
+	unlock()
+	cache.counter.write(key, start, err)
+	if err != nil { return err }
+	
+	// 设置标签
+	if err = cache.tagged(key); err != nil { return err }
+	// 重置配置
+	cache.Reset()
+	
+	return nil
+}
+
+// tagged - 在标签索引锁内把缓存加入标签索引，避免多进程并发写入时丢失标签成员 - 辅助方法
+func (this *FileClass) tagged(key ...string) (err error) {
+	
+	if len(this.Body.Tags) == 0 || len(key) == 0 { return nil }
+	
+	unlock, err := this.flock()
+	if err != nil { return err }
+	defer unlock()
+	
+	this.setTags(key...)
+	
+	return nil
+}
+
+// record - 按当前过期时间生成缓存文件内容 - 辅助方法
+func (this *FileClass) record(key string, data []byte) []byte {
+	
+	row := FileCacheResp{
+		// 过期时间戳
+		Expired: time.Now().Add(this.Body.Expired).Unix(),
+		Key:     key,
+		Codec:   this.Codec().Name(),
+		Value:   data,
 	}
-	// 合并之前的标签
-	if len(cache.Body.Keys) > 0 {
-		keys = append(keys, cache.Body.Keys...)
+	
+	// JSON 编码的值直接内嵌，保持缓存文件可读且兼容旧格式
+	if row.Codec == "json" && JSON.Valid(data) {
+		row.Codec = ""
+		row.Value = JSON.RawMessage(data)
 	}
-	// 去重
-	cache.Body.Keys = cast.ToStringSlice(utils.ArrayUnique(keys))
-	return cache
+	
+	return []byte(utils.Json.Encode(row))
+}
+
+// TTL - 获取缓存剩余存活时间
+func (this *FileClass) TTL(key string) (ttl time.Duration, err error) {
+	
+	if utils.Is.Empty(key) { return 0, ErrCacheKeyEmpty }
+	
+	row, err := this.row(key, true)
+	if err != nil { return 0, err }
+	
+	return time.Until(time.Unix(row.Expired, 0)), nil
+}
+
+// Touch - 重新设置缓存的过期时间
+func (this *FileClass) Touch(key string, ttl any) (ok bool) {
+	
+	if utils.Is.Empty(key) { return false }
+	
+	unlock, err := this.klock(this.Name(key))
+	if err != nil { return false }
+	defer unlock()
+	
+	row, err := this.row(key, false)
+	if err != nil { return false }
+	
+	cache, _ := this.Expired(ttl).(*FileClass)
+	row.Expired = time.Now().Add(cache.Body.Expired).Unix()
+	
+	return this.Write(this.Dest(key), []byte(utils.Json.Encode(row))) == nil
+}
+
+// GetMany - 批量获取缓存（并行读取文件）
+func (this *FileClass) GetMany(keys []string) (values map[string]any) {
+	
+	items := make(map[string][]byte, len(keys))
+	
+	var mutex sync.Mutex
+	this.parallel(len(keys), func(i int) {
+		data, err := this.GetRaw(context.Background(), keys[i])
+		if err != nil { return }
+		mutex.Lock()
+		items[keys[i]] = data
+		mutex.Unlock()
+	})
+	
+	return CacheInst.decodeMany(this.Codec(), items)
 }
 
-// Tag - 标签
-func (this *FileClass) Tag(tag ...string) CacheAPI {
+// SetMany - 批量设置缓存（并行写入文件，每个文件只锁所在分片）
+func (this *FileClass) SetMany(values map[string]any) (ok bool) {
+	
 	cache := this.clone()
-	var tags []string
-	// 把多个标签转换为数组
-	for _, value := range tag {
-		tags = append(tags, strings.ToUpper(fmt.Sprintf("tag-%v", value)))
-	}
-	// 合并之前的标签
-	if len(cache.Body.Tags) > 0 {
-		tags = append(tags, cache.Body.Tags...)
+	if cache == nil { return false }
+	
+	items, err := CacheInst.encodeMany(cache.Codec(), values)
+	if err != nil { return false }
+	
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
 	}
-	// 去重
-	cache.Body.Tags = cast.ToStringSlice(utils.ArrayUnique(tags))
-	return cache
+	
+	start := time.Now()
+	
+	var failed atomic.Bool
+	cache.parallel(len(keys), func(i int) {
+		unlock, err := cache.klock(cache.Name(keys[i]))
+		if err == nil {
+			err = cache.Write(cache.Dest(keys[i]), cache.record(keys[i], items[keys[i]]))
+			unlock()
+		}
+		cache.counter.write(keys[i], start, err)
+		if err != nil { failed.Store(true) }
+	})
+	
+	// 设置标签
+	if cache.tagged(keys...) != nil { failed.Store(true) }
+	// 重置配置
+	cache.Reset()
+	
+	return !failed.Load()
 }
 
-// Tags - 标签
-func (this *FileClass) Tags(tag []string) CacheAPI {
-	cache := this.clone()
-	var tags []string
-	// 把多个标签转换为数组
-	for _, value := range tag {
-		tags = append(tags, strings.ToUpper(fmt.Sprintf("tag-%v", value)))
-	}
-	// 合并之前的标签
-	if len(cache.Body.Tags) > 0 {
-		tags = append(tags, cache.Body.Tags...)
+// DeleteByPattern - 按通配符删除缓存
+/**
+ * 只能匹配记录了原始 key 的缓存，规则同 Scan。
+ */
+func (this *FileClass) DeleteByPattern(pattern string) (count int, err error) {
+	
+	keys, err := this.Scan(pattern)
+	if err != nil || len(keys) == 0 { return 0, err }
+	
+	if err = this.clone().DeleteCtx(context.Background(), CacheInst.markers(keys)...); err != nil { return 0, err }
+	
+	return len(keys), nil
+}
+
+// parallel - 以有限的并发数执行 n 个任务 - 辅助方法
+func (this *FileClass) parallel(n int, fn func(i int)) {
+	
+	workers := min(n, 16)
+	tasks   := make(chan int)
+	
+	var wait sync.WaitGroup
+	for range workers {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			for i := range tasks { fn(i) }
+		}()
 	}
-	// 去重
-	cache.Body.Tags = cast.ToStringSlice(utils.ArrayUnique(tags))
-	return cache
+	
+	for i := range n { tasks <- i }
+	close(tasks)
+	
+	wait.Wait()
 }
 
-// SetTags - 设置标签
-func (this *FileClass) SetTags(key string) {
-	for _, tag := range this.Body.Tags {
+// Export - 以 JSON Lines 导出所有未过期的缓存
+/**
+ * 只能导出记录了原始 key 的缓存，旧版本写入的缓存文件会被忽略。
+ */
+func (this *FileClass) Export(w io.Writer) (count int, err error) {
+	return CacheInst.export(w, this.Codec(), this.records)
+}
+
+// Import - 导入 Export 导出的缓存
+func (this *FileClass) Import(r io.Reader) (count int, err error) {
+	return CacheInst.load(this, r)
+}
+
+// records - 遍历未过期的缓存及其标签 - 辅助方法
+func (this *FileClass) records(yield func(item cacheRecord) error) (err error) {
+	
+	// 缓存名称 -> 标签
+	tags := make(map[string][]string)
+	
+	items, err := afero.ReadDir(this.Fs, this.Root)
+	if err != nil && !os.IsNotExist(err) { return err }
+	
+	prefix := fmt.Sprintf("%v-", this.Body.Prefix)
+	suffix := fmt.Sprintf(".%s", this.Suffix)
+	
+	for _, item := range items {
 		
-		var value []byte
-		var keys  []string
+		name := item.Name()
+		if item.IsDir() || !strings.HasPrefix(name, prefix + "TAG-") || !strings.HasSuffix(name, suffix) { continue }
 		
-		// 文件名
-		name := fmt.Sprintf("%v-%v.%s", this.Body.Prefix, tag, this.Suffix)
-		// 存储路径
-		dest := fmt.Sprintf("%s/%s", this.Root, name)
+		read, err := this.Read(filepath.Join(this.Root, name))
+		if err != nil { continue }
 		
-		// 不存在标签
-		if !this.Exist(dest) {
-			// 添加新成员
-			keys = append(keys, this.Name(key))
-			value = []byte(utils.Json.Encode(keys))
-			_ = this.Write(dest, value)
-			continue
+		tag := CacheInst.exportTag(strings.TrimSuffix(strings.TrimPrefix(name, prefix), suffix))
+		for _, member := range cast.ToStringSlice(utils.Json.Decode(read)) {
+			tags[member] = append(tags[member], tag)
 		}
-		
-		// 获取标签下的所有成员
-		read, _ := this.Read(dest)
-		keys  = cast.ToStringSlice(utils.Json.Decode(read))
-		
-		// 添加新成员
-		keys  = append(keys, this.Name(key))
-		// 去重
-		keys  = cast.ToStringSlice(utils.ArrayUnique(keys))
-		// 重新设置
-		value = []byte(utils.Json.Encode(keys))
-		// 写入文件
-		_ = this.Write(dest, value)
 	}
-}
-
-// DelTags - 删除标签
-func (this *FileClass) DelTags() {
-	for _, tag := range this.Body.Tags {
+	
+	now := time.Now()
+	
+	var failed error
+	skipped := &CacheSkipError{}
+	err = this.walk(func(dest string, row *fileCacheRow, size int64) {
 		
-		// 文件名
-		name := fmt.Sprintf("%v-%v.%s", this.Body.Prefix, tag, this.Suffix)
-		// 存储路径
-		dest := fmt.Sprintf("%s/%s", this.Root, name)
+		if failed != nil || row.Expired < now.Unix() { return }
 		
-		// 不存在标签
-		if !this.Exist(dest) {
-			continue
+		// 没有记录原始 key 的旧文件无法还原
+		if row.Key == "" {
+			skipped.Keys = append(skipped.Keys, filepath.Base(dest))
+			if skipped.Err == nil { skipped.Err = fmt.Errorf("缓存文件没有记录原始 key") }
+			return
 		}
 		
-		// 获取标签下的所有成员
-		read, _ := this.Read(dest)
-		keys := cast.ToStringSlice(utils.Json.Decode(read))
-		// 删除标签下的所有成员
-		for _, key := range keys {
-			_ = this.DeleteFile(fmt.Sprintf("%s/%s", this.Root, key))
+		data, err := row.bytes()
+		if err != nil {
+			skipped.Keys = append(skipped.Keys, row.Key)
+			if skipped.Err == nil { skipped.Err = err }
+			return
 		}
-		// 删除标签文件
-		_ = this.DeleteFile(dest)
-	}
+		
+		failed = yield(cacheRecord{
+			key:  row.Key,
+			data: data,
+			ttl:  max(time.Unix(row.Expired, 0).Sub(now), time.Second),
+			tags: tags[this.Name(row.Key)],
+		})
+	})
+	if failed != nil { return failed }
+	if err == nil && len(skipped.Keys) > 0 { return skipped }
+	
+	return err
 }
 
-// Expired - 过期时间
-func (this *FileClass) Expired(second any) CacheAPI {
+// Incr - 原子自增
+func (this *FileClass) Incr(key string, delta int64) (value int64, err error) {
+	return this.IncrWithTTL(key, delta, nil)
+}
+
+// Decr - 原子自减
+func (this *FileClass) Decr(key string, delta int64) (value int64, err error) {
+	return this.IncrWithTTL(key, -delta, nil)
+}
+
+// IncrWithTTL - 原子自增，仅在首次创建时设置过期时间
+/**
+ * 读取和写回在文件锁内完成，多个进程共用同一个存储目录时也不会丢失计数。
+ */
+func (this *FileClass) IncrWithTTL(key string, delta int64, ttl any) (value int64, err error) {
+	
 	cache := this.clone()
+	if cache == nil { return 0, fmt.Errorf("文件缓存未初始化") }
+	if utils.Is.Empty(key) { return 0, ErrCacheKeyEmpty }
 	
-	// 判断 second 是否为 time.Duration 类型或可解析为 duration 字符串
-	switch v := second.(type) {
-	case time.Duration:
-		cache.Body.Expired = v
-	case string:
-		// 尝试解析为 duration 字符串（例如 `5s`, `1m`）
-		if d, err := time.ParseDuration(v); err == nil {
-			cache.Body.Expired = d
-		} else if i := cast.ToInt64(v); i > 0 {
-			cache.Body.Expired = time.Duration(i) * time.Second
-		} else {
-			cache.Body.Expired = cast.ToDuration(v)
-		}
-	default:
-		// 对于数值类型，按秒处理；对于其他类型，尽量转换为 duration
-		if cast.ToInt64(second) > 0 {
-			cache.Body.Expired = time.Duration(cast.ToInt64(second)) * time.Second
-		} else {
-			// 一百年
-			cache.Body.Expired = cast.ToDuration(100*365*24*60*60) * time.Second
+	if ttl != nil { cache, _ = cache.Expired(ttl).(*FileClass) }
+	// 重置配置
+	defer cache.Reset()
+	
+	unlock, err := cache.klock(cache.Name(key))
+	if err != nil { return 0, err }
+	defer unlock()
+	
+	row, err := cache.row(key, false)
+	created := errors.Is(err, ErrCacheMiss)
+	if err != nil && !created { return 0, err }
+	
+	if created {
+		row = &fileCacheRow{ Expired: time.Now().Add(cache.Body.Expired).Unix() }
+	} else {
+		data, err := row.bytes()
+		if err != nil { return 0, err }
+		if value, err = strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64); err != nil {
+			return 0, fmt.Errorf("缓存值不是整数: %w", err)
 		}
 	}
 	
-	return cache
-}
-
-// Has - 判断缓存是否存在
-func (this *FileClass) Has(key string) (ok bool) {
+	value += delta
 	
-	// 如果 key 为空，直接返回 false
-	if utils.Is.Empty(key) {
-		return false
-	}
-	// 检查缓存是否存在
-	if !this.Exist(this.Dest(key)) {
-		return false
-	}
+	row.Key   = key
+	row.Codec = ""
+	row.Value = JSON.RawMessage(strconv.FormatInt(value, 10))
 	
-	// 读取文件内容
-	data, err := this.Read(this.Dest(key))
-	if err != nil {
-		return false
-	}
+	if err = cache.Write(cache.Dest(key), []byte(utils.Json.Encode(row))); err != nil { return 0, err }
 	
-	var row FileCacheResp
+	// 首次创建时设置标签
+	if created { err = cache.tagged(key) }
 	
-	_ = utils.Json.Unmarshal(data, &row)
+	return value, err
+}
+
+// fileLockShards - 缓存文件锁的分片数
+const fileLockShards = 64
+
+// flock - 获取标签索引的跨进程文件锁（标签索引、Clear 使用） - 辅助方法
+func (this *FileClass) flock() (unlock func(), err error) {
+	return this.acquire(this.lockFile())
+}
+
+// klock - 获取缓存文件所在分片的跨进程文件锁 - 辅助方法
+/**
+ * @param name 缓存名称（即缓存文件名）
+ */
+func (this *FileClass) klock(name string) (unlock func(), err error) {
 	
-	// 检查缓存是否过期
-	if row.Expired < time.Now().Unix() {
-		// 删除过期缓存
-		_ = this.DeleteFile(this.Dest(key))
-		return false
-	}
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(name))
 	
-	return true
+	return this.acquire(fmt.Sprintf("%s/.%v.%02d.lock", this.Root, this.Body.Prefix, hash.Sum32() % fileLockShards))
 }
 
-// Get - 获取缓存
-func (this *FileClass) Get(key string) (value any) {
+// acquire - 获取指定锁文件的排他锁 - 辅助方法
+func (this *FileClass) acquire(dest string) (unlock func(), err error) {
 	
-	if utils.Is.Empty(key) {
-		return nil
-	}
+	// 创建存储目录
+	_ = os.MkdirAll(this.Root, 0755)
 	
-	// 获取缓存内容
-	if !this.Has(key) {
-		return nil
-	}
+	file, err := os.OpenFile(dest, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil { return nil, fmt.Errorf("打开锁文件失败: %w", err) }
 	
-	// 读取文件内容
-	data, err := this.Read(this.Dest(key))
-	if err != nil {
-		return nil
+	if err = cacheFlock(file); err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("获取文件锁失败: %w", err)
 	}
 	
-	var row FileCacheResp
+	return func() {
+		_ = cacheFunlock(file)
+		_ = file.Close()
+	}, nil
+}
+
+// lockFile - 锁文件路径（以 . 开头，不会被当作缓存记录） - 辅助方法
+func (this *FileClass) lockFile() string {
+	return fmt.Sprintf("%s/.%v.lock", this.Root, this.Body.Prefix)
+}
+
+// Scan - 按通配符列出缓存的原始 key
+/**
+ * 只能列出记录了原始 key 的缓存，旧版本写入的缓存文件会被忽略。
+ */
+func (this *FileClass) Scan(pattern string) (keys []string, err error) {
 	
-	err = utils.Json.Unmarshal(data, &row)
-	if err != nil {
-		return nil
-	}
+	now := time.Now().Unix()
 	
-	// 检查缓存是否过期
-	if row.Expired < time.Now().Unix() {
-		// 删除过期缓存
-		_ = this.DeleteFile(this.Dest(key))
-		return nil
-	}
+	err = this.walk(func(dest string, row *fileCacheRow, size int64) {
+		if row.Expired < now { return }
+		if row.Key != "" && CacheInst.match(pattern, row.Key) {
+			keys = append(keys, row.Key)
+		}
+	})
 	
-	// 返回缓存值
-	return row.Value
+	return keys, err
 }
 
-// Set - 设置缓存
-func (this *FileClass) Set(key string, value any) (ok bool) {
-	cache := this.clone()
-	
-	if cache == nil || utils.Is.Empty(key) { return false }
+// Stats - 缓存统计
+func (this *FileClass) Stats() (stats CacheStats) {
 	
-	// 创建存储目录
-	_ = os.MkdirAll(cache.Root, 0755)
-	
-	// 过期时间戳
-	expired := time.Now().Add(cache.Body.Expired).Unix()
+	stats = this.counter.stats("file")
+	now  := time.Now().Unix()
 	
-	data := utils.Json.Encode(map[string]any{
-		"expired": expired,
-		"value":   value,
+	_ = this.walk(func(dest string, row *fileCacheRow, size int64) {
+		if row.Expired < now { return }
+		stats.Keys++
+		stats.Size += size
 	})
 	
-	if err := cache.Write(cache.Dest(key), []byte(data)); err != nil {
-		return false
-	}
+	return stats
+}
+
+// walk - 遍历存储目录下的缓存记录（包含已过期的记录，跳过标签文件） - 辅助方法
+func (this *FileClass) walk(fn func(dest string, row *fileCacheRow, size int64)) (err error) {
 	
-	// 设置标签
-	cache.SetTags(key)
-	// 重置配置
-	cache.Reset()
+	if !this.Exist(this.Root) { return nil }
+	
+	prefix := fmt.Sprintf("%v-", this.Body.Prefix)
+	suffix := fmt.Sprintf(".%s", this.Suffix)
+	tags   := fmt.Sprintf("%v-TAG-", this.Body.Prefix)
 	
-	return true
+	return afero.Walk(this.Fs, this.Root, func(dest string, info os.FileInfo, err error) error {
+		
+		if err != nil || info.IsDir() { return nil }
+		
+		name := info.Name()
+		if !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, suffix) { return nil }
+		if strings.HasPrefix(name, tags) { return nil }
+		
+		content, err := this.Read(dest)
+		if err != nil { return nil }
+		
+		var row fileCacheRow
+		if utils.Json.Unmarshal(content, &row) != nil { return nil }
+		
+		fn(dest, &row, info.Size())
+		return nil
+	})
 }
 
-// Delete - 删除缓存
-func (this *FileClass) Delete(key ...string) (ok bool) {
+// Codec - 当前使用的编解码器
+func (this *FileClass) Codec() CacheCodec {
+	if this.codec == nil { return CacheInst.codec("json") }
+	return this.codec
+}
+
+// Remember - 读取缓存，未命中时回源并写入
+func (this *FileClass) Remember(key string, ttl any, fn func() (any, error)) (value any, err error) {
+	return CacheRemember[any](this, key, ttl, fn)
+}
+
+// flight - 进程内请求合并组
+func (this *FileClass) flight() *singleflight.Group {
+	if this.group == nil { this.group = &singleflight.Group{} }
+	return this.group
+}
+
+// lock - 文件缓存仅在进程内合并请求，不需要额外的锁
+func (this *FileClass) lock(ctx context.Context, key string) (unlock func(), ok bool, err error) {
+	return func() {}, true, nil
+}
+
+// lockWait - 文件缓存不需要等待
+func (this *FileClass) lockWait() time.Duration { return 0 }
+
+// DeleteCtx - 删除缓存（支持上下文）
+func (this *FileClass) DeleteCtx(ctx context.Context, key ...string) (err error) {
+	
 	cache := this.clone()
-	if cache == nil {
-		return false
-	}
+	if cache == nil { return fmt.Errorf("文件缓存未初始化") }
+	if err = ctx.Err(); err != nil { return err }
 	
 	if cloned, ok := cache.Keys(key).(*FileClass); ok {
 		cache = cloned
 	}
 	
+	start := time.Now()
+	
 	// 删除缓存
 	for _, value := range cache.Body.Keys {
-		_ = cache.DeleteFile(fmt.Sprintf("%s/%s", cache.Root, value))
+		if err = ctx.Err(); err != nil { return err }
+		unlock, err := cache.klock(value)
+		if err != nil { return err }
+		_ = cache.DeleteFile(cache.path(value))
+		unlock()
 	}
 	
 	// 根据标签删除缓存
-	cache.DelTags()
+	if len(cache.Body.Tags) > 0 {
+		unlock, err := cache.flock()
+		if err != nil { return err }
+		cache.delTags()
+		unlock()
+	}
+	cache.counter.remove(key, cache.Body.Tags, start, nil)
 	// 重置配置
 	cache.Reset()
 	
-	return true
+	return nil
 }
 
-// Clear - 清空缓存
-func (this *FileClass) Clear() (ok bool) {
-	err := this.DeleteFile(this.Root)
-	if err != nil {
-		return false
-	}
-	// 创建目录
-	_ = os.MkdirAll(this.Root, 0755)
-	return true
+// ClearCtx - 清空缓存（支持上下文）
+func (this *FileClass) ClearCtx(ctx context.Context) (err error) {
+	
+	if err = ctx.Err(); err != nil { return err }
+	
+	unlock, err := this.flock()
+	if err != nil { return err }
+	defer unlock()
+	
+	if !this.Exist(this.Root) { return nil }
+	
+	// 只删除当前前缀的缓存和标签文件：同一目录下可能有其他前缀的实例，锁文件也需要保留
+	prefix := fmt.Sprintf("%v-", this.Body.Prefix)
+	
+	return afero.Walk(this.Fs, this.Root, func(dest string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !strings.HasPrefix(info.Name(), prefix) { return err }
+		return this.DeleteFile(dest)
+	})
 }
 
 // Name - 缓存名称规则 - 辅助方法
@@ -950,9 +2870,21 @@ func (this *FileClass) Name(key string) string {
 	return fmt.Sprintf("%s-%s.%s", this.Body.Prefix, utils.Hash.Sum32(key), this.Suffix)
 }
 
-// Dest - 缓存目录 - 辅助方法
+// Dest - 缓存文件路径 - 辅助方法
 func (this *FileClass) Dest(key string) string {
-	return fmt.Sprintf("%s/%s", this.Root, this.Name(key))
+	return this.path(this.Name(key))
+}
+
+// path - 按缓存名称计算文件路径（开启 Shard 时按名称哈希分到两级子目录） - 辅助方法
+func (this *FileClass) path(name string) string {
+	
+	if !this.Config.Shard { return fmt.Sprintf("%s/%s", this.Root, name) }
+	
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(name))
+	sum  := fmt.Sprintf("%08x", hash.Sum32())
+	
+	return fmt.Sprintf("%s/%s/%s/%s", this.Root, sum[0:2], sum[2:4], name)
 }
 
 // Reset - 重置配置 - 辅助方法
@@ -975,26 +2907,51 @@ func (this *FileClass) Chmod(dest string, mode int64) {
 	return
 }
 
-// CreateFile - 创建文件并写入内容 - 辅助方法
+// Write - 写入文件内容（先写临时文件再重命名，避免写入中途崩溃留下损坏的文件） - 辅助方法
 func (this *FileClass) Write(dest string, content []byte) (err error) {
 	
-	// 以写入模式打开文件（若不存在则创建，存在则覆盖）
-	file, err := this.Fs.Create(dest)
+	// 确保目标目录存在
+	if err = this.Fs.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return fmt.Errorf("创建目标目录失败: %w", err)
+	}
 	
+	// 在同一目录下创建临时文件，避免跨设备重命名
+	file, err := afero.TempFile(this.Fs, filepath.Dir(dest), ".tmp-*")
 	if err != nil {
-		return fmt.Errorf("创建文件失败: %w", err)
+		return fmt.Errorf("创建临时文件失败: %w", err)
 	}
-	// 延迟关闭文件
-	defer func(file afero.File) { err = file.Close() }(file)
 	
-	// 写入内容到文件
-	_, err = file.Write(content)
-	if err != nil {
+	temp := file.Name()
+	// 失败时清理临时文件
+	cleanup := func() { _ = this.Fs.Remove(temp) }
+	
+	// 写入内容到临时文件
+	if _, err = file.Write(content); err != nil {
+		_ = file.Close()
+		cleanup()
 		return fmt.Errorf("写入文件内容失败: %w", err)
 	}
 	
+	// 刷新到磁盘
+	if err = file.Sync(); err != nil {
+		_ = file.Close()
+		cleanup()
+		return fmt.Errorf("同步临时文件失败: %w", err)
+	}
+	
+	if err = file.Close(); err != nil {
+		cleanup()
+		return fmt.Errorf("关闭临时文件失败: %w", err)
+	}
+	
 	// 设置文件权限为 755
-	err = this.Fs.Chmod(dest, 0755)
+	_ = this.Fs.Chmod(temp, 0755)
+	
+	// 重命名为目标文件（在大多数系统上具有原子性）
+	if err = this.Fs.Rename(temp, dest); err != nil {
+		cleanup()
+		return fmt.Errorf("重命名文件失败: %w", err)
+	}
 	
 	return nil
 }
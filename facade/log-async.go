@@ -0,0 +1,160 @@
+package facade
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// logOutput - 日志通道的本地输出
+type logOutput struct {
+	mutex  sync.RWMutex
+	// 按日期切换目录的文件
+	daily  *logDaily
+	// 异步缓冲（未启用时为 nil）
+	buffer *zapcore.BufferedWriteSyncer
+	// 是否已关闭
+	closed bool
+}
+
+// Write - 写入缓冲或文件；关闭后改为同步写入，避免日志进入已停止的缓冲而丢失
+func (this *logOutput) Write(data []byte) (n int, err error) {
+
+	this.mutex.RLock()
+	defer this.mutex.RUnlock()
+
+	if this.closed {
+		// 写完立即关闭文件，避免关闭后的零星写入一直占用文件句柄
+		n, err = this.daily.Write(data)
+		return n, errors.Join(err, this.daily.Close())
+	}
+
+	if this.buffer != nil { return this.buffer.Write(data) }
+
+	return this.daily.Write(data)
+}
+
+// Sync - 缓冲落盘
+func (this *logOutput) Sync() error {
+
+	this.mutex.RLock()
+	defer this.mutex.RUnlock()
+
+	if this.buffer != nil && !this.closed { return this.buffer.Sync() }
+
+	return this.daily.Sync()
+}
+
+// close - 落盘并关闭文件
+func (this *logOutput) close() (err error) {
+
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if this.closed { return nil }
+	this.closed = true
+
+	var errs []error
+	if this.buffer != nil { errs = append(errs, this.buffer.Stop()) }
+	errs = append(errs, this.daily.Close())
+
+	return errors.Join(errs...)
+}
+
+// Flush - 把所有级别通道缓冲中的日志落盘，并等待远程输出发送完
+func (this *LogClass) Flush() (err error) {
+
+	this.ensureLog()
+
+	item := logActive.Load()
+	if item == nil { return nil }
+
+	return item.flush()
+}
+
+// Flush - 把缓冲中的日志落盘，并等待远程输出发送完（子日志作用于其根实例）
+func (this *log) Flush() (err error) {
+	return this.current().flush()
+}
+
+// Close - 落盘并关闭日志的所有输出，停止远程输出的发送协程（子日志作用于其根实例）
+/**
+ * @example：
+ * custom := facade.Log.NewLog(dto.LogConfig{Sinks: sinks})
+ * defer custom.Close()
+ */
+func (this *log) Close() (err error) {
+	return this.current().close()
+}
+
+// Close - 落盘并关闭当前日志的所有输出（程序退出前调用）
+/**
+ * 关闭后本地日志改为同步写入文件，远程输出的日志会被丢弃并计入 Dropped。
+ * @example：
+ * defer facade.LogInst.Close()
+ */
+func (this *LogClass) Close() (err error) {
+
+	this.ensureLog()
+
+	item := logActive.Load()
+	if item == nil { return nil }
+
+	return item.close()
+}
+
+// newChannel - 创建级别通道（本地输出 + 远程输出 + 采样）
+func (this *log) newChannel(level string) *zap.Logger {
+
+	logger, output := LogInst.newLevel(level, this.Config)
+	this.outputs = append(this.outputs, output)
+
+	logger = this.sinks.wrap(logger)
+
+	// 只对 debug 采样，其他级别的日志全部保留
+	if level == "debug" && this.Config.Sampling.Enable {
+		sampling := this.Config.Sampling
+		logger = logger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			return zapcore.NewSamplerWithOptions(core, time.Second, sampling.Initial, sampling.Thereafter)
+		}))
+	}
+
+	// 级别过滤放在最外层，被过滤的日志不计入采样
+	return newLogLevelCore(logger, this.levels, this.module)
+}
+
+// flush - 落盘并等待远程输出发送完
+func (this *log) flush() (err error) {
+
+	var errs []error
+	for _, logger := range []*zap.Logger{this.InfoLogger, this.WarnLogger, this.ErrorLogger, this.DebugLogger} {
+		if logger != nil { errs = append(errs, logger.Sync()) }
+	}
+	errs = append(errs, this.sinks.flush())
+
+	return errors.Join(errs...)
+}
+
+// close - 落盘并关闭所有输出，等待远程输出发送完
+func (this *log) close() (err error) {
+
+	var errs []error
+	for _, output := range this.outputs {
+		errs = append(errs, output.close())
+	}
+	errs = append(errs, this.sinks.stop())
+
+	return errors.Join(errs...)
+}
+
+// release - 替换为新实例后释放旧实例的输出（不等待远程输出）
+func (this *log) release() {
+
+	for _, output := range this.outputs {
+		_ = output.close()
+	}
+	this.sinks.close()
+}
@@ -0,0 +1,133 @@
+package facade
+
+import (
+	"context"
+	"maps"
+	"sync"
+
+	"github.com/inis-io/aide/utils"
+)
+
+// LogTraceFunc - 从上下文中提取链路 ID
+/**
+ * @example：
+ * // 接入 OpenTelemetry
+ * facade.LogInst.SetTrace(func(ctx context.Context) (string, string) {
+ *     span := trace.SpanContextFromContext(ctx)
+ *     if !span.IsValid() { return "", "" }
+ *     return span.TraceID().String(), span.SpanID().String()
+ * })
+ */
+type LogTraceFunc func(ctx context.Context) (traceID, spanID string)
+
+// logContextKey - 上下文中日志字段的键
+type logContextKey struct{}
+
+// logTrace - 全局链路 ID 提取函数
+var logTrace = struct {
+	Mutex sync.RWMutex
+	Func  LogTraceFunc
+}{}
+
+// SetTrace - 设置链路 ID 提取函数（传 nil 取消）
+func (this *LogClass) SetTrace(fn LogTraceFunc) {
+	logTrace.Mutex.Lock()
+	defer logTrace.Mutex.Unlock()
+	logTrace.Func = fn
+}
+
+// NewContext - 把请求级的日志字段存入上下文
+/**
+ * 同一个上下文多次调用时字段会合并，后设置的覆盖先设置的。
+ * @example：
+ * ctx = facade.LogInst.NewContext(ctx, map[string]any{"request_id": rid, "user_id": uid})
+ * facade.LogInst.FromContext(ctx).Info(nil, "create order")
+ */
+func (this *LogClass) NewContext(ctx context.Context, fields map[string]any) context.Context {
+
+	if ctx == nil { ctx = context.Background() }
+
+	merged := maps.Clone(this.contextFields(ctx))
+	if merged == nil { merged = make(map[string]any, len(fields)) }
+	maps.Copy(merged, fields)
+
+	return context.WithValue(ctx, logContextKey{}, merged)
+}
+
+// WithTrace - 把链路 ID 存入上下文（没有接入链路追踪时使用）
+func (this *LogClass) WithTrace(ctx context.Context, traceID, spanID string) context.Context {
+
+	fields := map[string]any{}
+	if !utils.Is.Empty(traceID) { fields["trace_id"] = traceID }
+	if !utils.Is.Empty(spanID)  { fields["span_id"]  = spanID }
+
+	return this.NewContext(ctx, fields)
+}
+
+// FromContext - 获取携带上下文字段的全局日志实例
+func (this *LogClass) FromContext(ctx context.Context) LogAPI {
+	this.ensureLog()
+	return Log.WithContext(ctx)
+}
+
+// contextFields - 上下文中存储的日志字段
+func (this *LogClass) contextFields(ctx context.Context) map[string]any {
+
+	if ctx == nil { return nil }
+
+	fields, _ := ctx.Value(logContextKey{}).(map[string]any)
+	return fields
+}
+
+// traceFields - 上下文中的链路 ID 与请求级字段
+func (this *LogClass) traceFields(ctx context.Context) map[string]any {
+
+	fields := maps.Clone(this.contextFields(ctx))
+	if fields == nil { fields = map[string]any{} }
+
+	logTrace.Mutex.RLock()
+	fn := logTrace.Func
+	logTrace.Mutex.RUnlock()
+
+	if fn != nil && ctx != nil {
+		traceID, spanID := fn(ctx)
+		if !utils.Is.Empty(traceID) { fields["trace_id"] = traceID }
+		if !utils.Is.Empty(spanID)  { fields["span_id"]  = spanID }
+	}
+
+	return fields
+}
+
+// With - 创建携带固定字段的子日志
+/**
+ * @param fields 每条日志都会附带的字段，调用时传入的 data 同名时优先
+ * @example：
+ * logger := facade.Log.With(map[string]any{"module": "order"})
+ * logger.Info(map[string]any{"id": 1001}, "create order")
+ */
+func (this *log) With(fields map[string]any) LogAPI {
+
+	clone := this.derive()
+	clone.fields = maps.Clone(this.fields)
+	if clone.fields == nil { clone.fields = make(map[string]any, len(fields)) }
+	maps.Copy(clone.fields, fields)
+
+	return clone
+}
+
+// WithContext - 创建携带上下文字段（链路 ID、请求级字段）的子日志
+func (this *log) WithContext(ctx context.Context) LogAPI {
+	return this.With(LogInst.traceFields(ctx))
+}
+
+// merge - 合并固定字段与本次调用的字段
+func (this *log) merge(data map[string]any) map[string]any {
+
+	if len(this.fields) == 0 { return data }
+	if len(data) == 0 { return this.fields }
+
+	merged := maps.Clone(this.fields)
+	maps.Copy(merged, data)
+
+	return merged
+}
@@ -0,0 +1,84 @@
+package facade
+
+import (
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/inis-io/aide/dto"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// logDaily - 按日期切换目录的日志写入器
+/**
+ * 文件按 <Dir>/<日期>/<级别>.log 写入，每次写入时检查当前日期，跨天后自动切到新目录；
+ * 同一天内仍由 lumberjack 按大小轮转。
+ */
+type logDaily struct {
+	mutex  sync.Mutex
+	// 日志目录
+	dir    string
+	// 级别名称（文件名）
+	level  string
+	// 当前配置
+	config dto.LogConfig
+	// 当前写入的文件
+	writer *lumberjack.Logger
+	// 当前文件的有效期（下一个零点）
+	until  time.Time
+}
+
+// newLogDaily - 创建按日期切换目录的日志写入器
+func newLogDaily(level string, config dto.LogConfig) *logDaily {
+	return &logDaily{
+		dir:    config.Dir,
+		level:  level,
+		config: config,
+	}
+}
+
+// Write - 写入日志，跨天时切换文件
+func (this *logDaily) Write(data []byte) (n int, err error) {
+
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	return this.current(time.Now()).Write(data)
+}
+
+// Sync - lumberjack 每次写入都直接落盘，这里无需处理
+func (this *logDaily) Sync() error { return nil }
+
+// Close - 关闭当前文件
+func (this *logDaily) Close() (err error) {
+
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if this.writer == nil { return nil }
+
+	err = this.writer.Close()
+	this.writer = nil
+	return err
+}
+
+// current - 获取当前日期对应的写入器（调用方需持有锁）
+func (this *logDaily) current(now time.Time) *lumberjack.Logger {
+
+	if this.writer != nil && now.Before(this.until) { return this.writer }
+
+	if this.writer != nil { _ = this.writer.Close() }
+
+	year, month, day := now.Date()
+	this.until  = time.Date(year, month, day+1, 0, 0, 0, 0, now.Location())
+	this.writer = &lumberjack.Logger{
+		Filename:   filepath.Join(this.dir, now.Format("2006-01-02"), this.level + ".log"),
+		MaxAge:     this.config.Age,
+		MaxSize:    this.config.Size,
+		MaxBackups: this.config.Backups,
+		Compress:   this.config.Compress,
+		LocalTime:  this.config.LocalTime,
+	}
+
+	return this.writer
+}
@@ -0,0 +1,190 @@
+package facade
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/inis-io/aide/dto"
+	"github.com/inis-io/aide/utils"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// logLevels - 日志级别（全局级别 + 模块级别覆盖，同一个根日志的所有子日志共享）
+type logLevels struct {
+	// 全局级别
+	level   zap.AtomicLevel
+	// 模块名称 -> 级别
+	modules sync.Map
+}
+
+// newLogLevels - 按配置创建日志级别
+func newLogLevels(config dto.LogConfig) *logLevels {
+
+	item := &logLevels{level: zap.NewAtomicLevelAt(zapcore.DebugLevel)}
+	item.reload(dto.LogConfig{}, config)
+
+	return item
+}
+
+// reload - 按新配置更新级别，只应用与旧配置不同的项，运行时通过 SetLevel 修改的级别会保留
+func (this *logLevels) reload(old, config dto.LogConfig) {
+
+	if config.Level != old.Level && !utils.Is.Empty(config.Level) {
+		if level, err := zapcore.ParseLevel(config.Level); err == nil { this.level.SetLevel(level) }
+	}
+
+	for module := range old.Modules {
+		if _, ok := config.Modules[module]; !ok { _ = this.set("", module) }
+	}
+
+	for module, value := range config.Modules {
+		if previous, ok := old.Modules[module]; !ok || previous != value { _ = this.set(value, module) }
+	}
+}
+
+// set - 设置全局或模块的级别，模块级别传空字符串时取消覆盖
+func (this *logLevels) set(value string, module ...string) (err error) {
+
+	name := strings.Trim(strings.Join(module, "."), ".")
+
+	if !utils.Is.Empty(name) && utils.Is.Empty(value) {
+		this.modules.Delete(name)
+		return nil
+	}
+
+	// ParseLevel 会把空字符串解析为 info，这里需要明确拒绝
+	if utils.Is.Empty(value) { return fmt.Errorf("日志级别不能为空") }
+
+	level, err := zapcore.ParseLevel(value)
+	if err != nil { return fmt.Errorf("日志级别无效: %w", err) }
+
+	if utils.Is.Empty(name) {
+		this.level.SetLevel(level)
+		return nil
+	}
+
+	if item, ok := this.modules.Load(name); ok {
+		item.(zap.AtomicLevel).SetLevel(level)
+		return nil
+	}
+
+	this.modules.Store(name, zap.NewAtomicLevelAt(level))
+	return nil
+}
+
+// get - 模块当前生效的级别（payment.gateway 未设置时依次查找 payment、全局）
+func (this *logLevels) get(module string) zap.AtomicLevel {
+
+	for name := module; !utils.Is.Empty(name); {
+
+		if item, ok := this.modules.Load(name); ok { return item.(zap.AtomicLevel) }
+
+		index := strings.LastIndex(name, ".")
+		if index < 0 { break }
+		name = name[:index]
+	}
+
+	return this.level
+}
+
+// enabled - 模块是否输出该级别的日志
+func (this *logLevels) enabled(module string, level zapcore.Level) bool {
+	if this == nil { return true }
+	return this.get(module).Enabled(level)
+}
+
+// logLevelCore - 按全局与模块级别过滤的 zap Core（直接使用 LogInfo 等通道时同样生效）
+type logLevelCore struct {
+	zapcore.Core
+	levels *logLevels
+	module string
+}
+
+// newLogLevelCore - 给日志通道加上级别过滤
+func newLogLevelCore(logger *zap.Logger, levels *logLevels, module string) *zap.Logger {
+
+	if logger == nil || levels == nil { return logger }
+
+	return logger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		// 已经包装过时只替换模块名称，避免重复过滤
+		if item, ok := core.(*logLevelCore); ok { core = item.Core }
+		return &logLevelCore{Core: core, levels: levels, module: module}
+	}))
+}
+
+// Enabled - 是否输出该级别的日志
+func (this *logLevelCore) Enabled(level zapcore.Level) bool {
+	return this.levels.enabled(this.module, level) && this.Core.Enabled(level)
+}
+
+// With - 附加字段
+func (this *logLevelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &logLevelCore{Core: this.Core.With(fields), levels: this.levels, module: this.module}
+}
+
+// Check - 检查级别
+func (this *logLevelCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !this.levels.enabled(this.module, entry.Level) { return checked }
+	return this.Core.Check(entry, checked)
+}
+
+// SetLevel - 修改日志级别，立即生效
+/**
+ * 级别保存在 LogInst 上，重新 Init 或配置热更新后仍然保留（配置中对应的项发生变化时除外）。
+ * @param level  级别：debug / info / warn / error
+ * @param module 模块名称，不传时修改全局级别；模块级别传空字符串时取消覆盖
+ * @example：
+ * facade.LogInst.SetLevel("warn")             // 全局只输出 warn 以上
+ * facade.LogInst.SetLevel("debug", "payment") // payment 模块单独打开 debug
+ * facade.LogInst.SetLevel("", "payment")      // 取消 payment 模块的覆盖
+ */
+func (this *LogClass) SetLevel(level string, module ...string) (err error) {
+
+	this.ensureLog()
+
+	if this.levels == nil { return fmt.Errorf("日志未初始化") }
+
+	return this.levels.set(level, module...)
+}
+
+// GetLevel - 获取当前生效的日志级别
+/**
+ * @param module 模块名称，不传时获取全局级别
+ */
+func (this *LogClass) GetLevel(module ...string) string {
+
+	this.ensureLog()
+
+	if this.levels == nil { return "" }
+
+	return this.levels.get(strings.Trim(strings.Join(module, "."), ".")).String()
+}
+
+// Named - 创建模块日志（日志中附带 logger 字段，可单独设置级别）
+/**
+ * @param name 模块名称，多次调用时以 . 连接，如 payment.gateway
+ * @example：
+ * pay := facade.Log.Named("payment")
+ * facade.LogInst.SetLevel("debug", "payment")
+ * pay.Debug(map[string]any{"order": 1001}, "callback")
+ */
+func (this *log) Named(name string) LogAPI {
+
+	name = strings.Trim(name, ".")
+	if utils.Is.Empty(name) { return this }
+
+	clone := this.derive()
+	if utils.Is.Empty(this.module) {
+		clone.module = name
+	} else {
+		clone.module = this.module + "." + name
+	}
+
+	// 带模块名称的通道在第一次写入时按根实例创建
+	clone.named = new(atomic.Pointer[logNamed])
+
+	return clone
+}
@@ -0,0 +1,239 @@
+package facade
+
+import (
+	JSON "encoding/json"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/inis-io/aide/dto"
+	"github.com/inis-io/aide/utils"
+	"github.com/spf13/cast"
+)
+
+// logRedactKeys - 内置的敏感字段（字段名已归一化）
+var logRedactKeys = map[string]string{
+	"password":     "password",
+	"passwd":       "password",
+	"pwd":          "password",
+	"secret":       "password",
+	"token":        "password",
+	"accesstoken":  "password",
+	"refreshtoken": "password",
+	"authorization":"password",
+	"apikey":       "password",
+	"phone":        "phone",
+	"mobile":       "phone",
+	"email":        "email",
+	"idcard":       "id_card",
+	"bankcard":     "bank_card",
+}
+
+// logRedactPattern - 按字段名正则匹配的规则
+type logRedactPattern struct {
+	regexp *regexp.Regexp
+	rule   string
+}
+
+// logRedactor - 日志脱敏器（按配置编译一次，并发安全）
+type logRedactor struct {
+	keys     map[string]string
+	patterns []logRedactPattern
+	detect   []string
+}
+
+// newLogRedactor - 按配置创建脱敏器，未启用时返回 nil
+func newLogRedactor(config dto.LogRedactConfig) *logRedactor {
+
+	if !config.Enable { return nil }
+
+	item := &logRedactor{keys: make(map[string]string, len(logRedactKeys) + len(config.Keys))}
+
+	for key, rule := range logRedactKeys {
+		item.keys[key] = rule
+	}
+	for key, rule := range config.Keys {
+		item.keys[item.normalize(key)] = strings.ToLower(rule)
+	}
+
+	// 按正则排序，保证多个规则同时匹配时结果稳定
+	patterns := make([]string, 0, len(config.Patterns))
+	for pattern := range config.Patterns {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+
+	for _, pattern := range patterns {
+		// 无效的正则直接忽略，避免因为配置错误导致日志不可用
+		if compiled, err := regexp.Compile(pattern); err == nil {
+			item.patterns = append(item.patterns, logRedactPattern{regexp: compiled, rule: strings.ToLower(config.Patterns[pattern])})
+		}
+	}
+
+	for _, rule := range config.Detect {
+		item.detect = append(item.detect, strings.ToLower(strings.TrimSpace(rule)))
+	}
+
+	return item
+}
+
+// redactor - 当前活动日志的脱敏器（未启用时为 nil）
+func (this *LogClass) redactor() *logRedactor {
+	if item := logActive.Load(); item != nil { return item.redact }
+	return nil
+}
+
+// apply - 返回脱敏后的字段（不修改原始数据）
+func (this *logRedactor) apply(data map[string]any) map[string]any {
+
+	if this == nil || len(data) == 0 { return data }
+
+	result := make(map[string]any, len(data))
+	for key, value := range data {
+		result[key] = this.field(key, value)
+	}
+
+	return result
+}
+
+// field - 按字段名或值脱敏
+func (this *logRedactor) field(key string, value any) any {
+
+	if value == nil { return nil }
+
+	if rule, ok := this.rule(key); ok {
+		return this.mask(rule, cast.ToString(value))
+	}
+
+	return this.value(value)
+}
+
+// value - 递归处理嵌套结构，字符串按值检测
+func (this *logRedactor) value(value any) any {
+
+	switch item := value.(type) {
+	case nil:
+		return nil
+	case string:
+		return this.detectValue(item)
+	case map[string]any:
+		return this.apply(item)
+	case map[string]string:
+		result := make(map[string]any, len(item))
+		for key, value := range item {
+			result[key] = this.field(key, value)
+		}
+		return result
+	case []any:
+		result := make([]any, len(item))
+		for i, value := range item {
+			result[i] = this.value(value)
+		}
+		return result
+	case []string:
+		result := make([]any, len(item))
+		for i, value := range item {
+			result[i] = this.detectValue(value)
+		}
+		return result
+	case error, JSON.Marshaler:
+		return value
+	}
+
+	// 结构体等复合类型先转换为通用结构再处理，保证嵌套的敏感字段也能被脱敏
+	switch reflect.Indirect(reflect.ValueOf(value)).Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		bytes, err := JSON.Marshal(value)
+		if err != nil { return value }
+		var result any
+		if err = JSON.Unmarshal(bytes, &result); err != nil { return value }
+		return this.value(result)
+	}
+
+	return value
+}
+
+// rule - 字段名对应的脱敏规则
+func (this *logRedactor) rule(key string) (rule string, ok bool) {
+
+	if rule, ok = this.keys[this.normalize(key)]; ok { return rule, true }
+
+	for _, item := range this.patterns {
+		if item.regexp.MatchString(key) { return item.rule, true }
+	}
+
+	return "", false
+}
+
+// detectValue - 按值检测并脱敏
+func (this *logRedactor) detectValue(value string) string {
+
+	for _, rule := range this.detect {
+		if this.detected(rule, value) { return this.mask(rule, value) }
+	}
+
+	return value
+}
+
+// detected - 值是否符合检测规则
+func (this *logRedactor) detected(rule, value string) bool {
+	switch rule {
+	case "phone":
+		return utils.Is.Phone(value)
+	case "email":
+		return utils.Is.Email(value) && !strings.ContainsAny(value, " \t\n")
+	case "id_card":
+		return utils.Is.IdCard(value)
+	case "bank_card":
+		return this.bankCard(value)
+	}
+	return false
+}
+
+// mask - 按规则脱敏，规则不适用时整体替换，避免敏感字段原样输出
+func (this *logRedactor) mask(rule, value string) string {
+
+	if utils.Is.Empty(value) { return value }
+
+	var result string
+	switch rule {
+	case "phone":
+		result = utils.Mask.Phone(value)
+	case "email":
+		result = utils.Mask.Email(value)
+	case "id_card":
+		result = utils.Mask.IDCard(value)
+		// 15 位身份证号
+		if result == value && len(value) > 8 { result = utils.Mask.Custom(value, 4, len(value) - 4) }
+	case "bank_card":
+		if len(value) > 8 { result = utils.Mask.BankCard(value) }
+	}
+
+	if result == "" || result == value { return utils.Mask.Password(value) }
+
+	return result
+}
+
+// bankCard - 是否为银行卡号（16 - 19 位数字且通过 Luhn 校验）
+func (this *logRedactor) bankCard(value string) bool {
+
+	if len(value) < 16 || len(value) > 19 { return false }
+
+	sum := 0
+	for i := len(value) - 1; i >= 0; i-- {
+		digit := int(value[i] - '0')
+		if digit < 0 || digit > 9 { return false }
+		if (len(value) - i) % 2 == 0 {
+			if digit *= 2; digit > 9 { digit -= 9 }
+		}
+		sum += digit
+	}
+
+	return sum % 10 == 0
+}
+
+// normalize - 字段名归一化（忽略大小写、下划线和中划线）
+func (this *logRedactor) normalize(key string) string {
+	return strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(strings.TrimSpace(key)))
+}
@@ -0,0 +1,576 @@
+package facade
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/inis-io/aide/dto"
+	"github.com/inis-io/aide/utils"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// LogSinkStats - 远程日志输出统计
+type LogSinkStats struct {
+	// 类型
+	Type    string
+	// 地址
+	Addr    string
+	// 发送成功的条数
+	Sent    int64
+	// 缓冲队列已满被丢弃的条数
+	Dropped int64
+	// 发送失败的条数（重试后仍失败）
+	Failed  int64
+}
+
+// SinkStats - 当前日志的远程输出统计
+func (this *LogClass) SinkStats() (stats []LogSinkStats) {
+
+	this.ensureLog()
+
+	item := logActive.Load()
+	if item == nil { return nil }
+
+	for _, sink := range item.sinks {
+		stats = append(stats, sink.stats())
+	}
+
+	return stats
+}
+
+// logSinkMessage - 待发送的日志
+type logSinkMessage struct {
+	level zapcore.Level
+	time  time.Time
+	line  []byte
+}
+
+// logSinkWriter - 远程输出的发送实现（只在发送协程中调用）
+type logSinkWriter interface {
+	write(items []logSinkMessage) error
+	close()
+}
+
+// logSinks - 同一个日志实例的全部远程输出
+type logSinks []*logSink
+
+// newLogSinks - 按配置创建远程输出，类型无效的配置会被忽略
+func newLogSinks(configs []dto.LogSinkConfig) (sinks logSinks) {
+
+	for _, config := range configs {
+		if sink := newLogSink(config); sink != nil { sinks = append(sinks, sink) }
+	}
+
+	return sinks
+}
+
+// wrap - 给日志通道追加远程输出
+func (this logSinks) wrap(logger *zap.Logger) *zap.Logger {
+
+	if len(this) == 0 || logger == nil { return logger }
+
+	return logger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		cores := []zapcore.Core{core}
+		for _, sink := range this {
+			cores = append(cores, sink.core())
+		}
+		return zapcore.NewTee(cores...)
+	}))
+}
+
+// close - 停止全部远程输出（不等待发送完成）
+func (this logSinks) close() {
+	for _, sink := range this {
+		sink.close()
+	}
+}
+
+// flush - 发送完全部远程输出队列中的日志
+func (this logSinks) flush() (err error) {
+
+	var errs []error
+	for _, sink := range this {
+		errs = append(errs, sink.flush())
+	}
+
+	return errors.Join(errs...)
+}
+
+// stop - 停止全部远程输出并等待发送完成
+func (this logSinks) stop() (err error) {
+
+	for _, sink := range this {
+		sink.close()
+	}
+
+	var errs []error
+	for _, sink := range this {
+		errs = append(errs, sink.wait())
+	}
+
+	return errors.Join(errs...)
+}
+
+// logSink - 远程日志输出（有界异步队列 + 单个发送协程）
+type logSink struct {
+	config  dto.LogSinkConfig
+	level   zapcore.Level
+	writer  logSinkWriter
+	queue   chan logSinkMessage
+	done    chan struct{}
+	stopped chan struct{}
+	flushes chan chan struct{}
+	once    sync.Once
+	sent    atomic.Int64
+	dropped atomic.Int64
+	failed  atomic.Int64
+}
+
+// newLogSink - 创建远程输出并启动发送协程
+func newLogSink(config dto.LogSinkConfig) *logSink {
+
+	config.Type    = strings.ToLower(strings.TrimSpace(config.Type))
+	config.Network = strings.ToLower(strings.TrimSpace(config.Network))
+
+	if utils.Is.Empty(config.Addr) { return nil }
+	if utils.Is.Empty(config.Network) { config.Network = "udp" }
+	if config.Buffer   <= 0 { config.Buffer   = 1024 }
+	if config.Batch    <= 0 { config.Batch    = 100 }
+	if config.Interval <= 0 { config.Interval = 1000 }
+	if config.Retry    == 0 { config.Retry    = 3 }
+	if config.Retry    <  0 { config.Retry    = 0 }
+	if config.Timeout  <= 0 { config.Timeout  = 5000 }
+
+	timeout := time.Duration(config.Timeout) * time.Millisecond
+
+	item := &logSink{
+		config:  config,
+		queue:   make(chan logSinkMessage, config.Buffer),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+		flushes: make(chan chan struct{}),
+	}
+
+	// ParseLevel 会把空字符串解析为 info，未设置时需要单独处理
+	item.level = zapcore.DebugLevel
+	if level, err := zapcore.ParseLevel(config.Level); err == nil && !utils.Is.Empty(config.Level) { item.level = level }
+
+	switch config.Type {
+	case "syslog":
+		item.writer = newLogSyslog(config, timeout)
+	case "tcp":
+		item.writer = &logSinkTCP{conn: &logSinkConn{network: "tcp", addr: config.Addr, timeout: timeout}}
+	case "http":
+		item.writer = &logSinkHTTP{
+			url:     config.Addr,
+			headers: config.Headers,
+			retry:   config.Retry,
+			client:  &http.Client{Timeout: timeout},
+		}
+	default:
+		return nil
+	}
+
+	go item.run()
+
+	return item
+}
+
+// core - 写入当前输出的 zap Core
+func (this *logSink) core() zapcore.Core {
+
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.TimeKey    = "time"
+	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+
+	return &logSinkCore{
+		LevelEnabler: this.level,
+		encoder:      zapcore.NewJSONEncoder(encoderConfig),
+		sink:         this,
+	}
+}
+
+// push - 写入缓冲队列，队列已满或输出已停止时丢弃
+func (this *logSink) push(item logSinkMessage) {
+
+	select {
+	case <-this.done:
+		this.dropped.Add(1)
+		return
+	default:
+	}
+
+	select {
+	case this.queue <- item:
+	default:
+		this.dropped.Add(1)
+	}
+}
+
+// run - 发送协程：按批次或间隔发送，停止时发送完队列中剩余的日志
+func (this *logSink) run() {
+
+	defer close(this.stopped)
+	defer this.writer.close()
+
+	size := 1
+	if this.config.Type == "http" { size = this.config.Batch }
+
+	ticker := time.NewTicker(time.Duration(this.config.Interval) * time.Millisecond)
+	defer ticker.Stop()
+
+	batch := make([]logSinkMessage, 0, size)
+	flush := func() {
+		if len(batch) == 0 { return }
+		if err := this.writer.write(batch); err != nil {
+			this.failed.Add(int64(len(batch)))
+		} else {
+			this.sent.Add(int64(len(batch)))
+		}
+		batch = batch[:0]
+	}
+
+	// 发送完队列中当前所有的日志
+	drain := func() {
+		for {
+			select {
+			case item := <-this.queue:
+				if batch = append(batch, item); len(batch) >= size { flush() }
+			default:
+				flush()
+				return
+			}
+		}
+	}
+
+	for {
+		select {
+		case item := <-this.queue:
+			if batch = append(batch, item); len(batch) >= size { flush() }
+		case <-ticker.C:
+			flush()
+		case ack := <-this.flushes:
+			drain()
+			close(ack)
+		case <-this.done:
+			drain()
+			return
+		}
+	}
+}
+
+// flush - 等待队列中当前的日志发送完
+func (this *logSink) flush() error {
+
+	timer := time.NewTimer(this.timeout())
+	defer timer.Stop()
+
+	ack := make(chan struct{})
+
+	select {
+	case this.flushes <- ack:
+	case <-this.stopped:
+		return nil
+	case <-timer.C:
+		return fmt.Errorf("日志发送超时: %s", this.config.Addr)
+	}
+
+	select {
+	case <-ack:
+		return nil
+	case <-this.stopped:
+		return nil
+	case <-timer.C:
+		return fmt.Errorf("日志发送超时: %s", this.config.Addr)
+	}
+}
+
+// wait - 等待发送协程退出
+func (this *logSink) wait() error {
+
+	timer := time.NewTimer(this.timeout())
+	defer timer.Stop()
+
+	select {
+	case <-this.stopped:
+		return nil
+	case <-timer.C:
+		return fmt.Errorf("日志发送超时: %s", this.config.Addr)
+	}
+}
+
+// timeout - 刷新与停止的最长等待时间（按超时与重试次数估算，HTTP 另加重试前的退避时间）
+func (this *logSink) timeout() time.Duration {
+
+	timeout := time.Duration(this.config.Timeout) * time.Millisecond * time.Duration(this.config.Retry + 2)
+
+	if this.config.Type == "http" {
+		for attempt := 1; attempt <= this.config.Retry; attempt++ { timeout += logSinkBackoff(attempt) }
+	}
+
+	return timeout
+}
+
+// close - 停止发送协程（队列中剩余的日志会继续发送完）
+func (this *logSink) close() {
+	this.once.Do(func() { close(this.done) })
+}
+
+// stats - 统计
+func (this *logSink) stats() LogSinkStats {
+	return LogSinkStats{
+		Type:    this.config.Type,
+		Addr:    this.config.Addr,
+		Sent:    this.sent.Load(),
+		Dropped: this.dropped.Load(),
+		Failed:  this.failed.Load(),
+	}
+}
+
+// logSinkCore - 把日志编码为 JSON 后写入远程输出的缓冲队列
+type logSinkCore struct {
+	zapcore.LevelEnabler
+	encoder zapcore.Encoder
+	sink    *logSink
+}
+
+// With - 附加字段
+func (this *logSinkCore) With(fields []zapcore.Field) zapcore.Core {
+
+	clone := *this
+	clone.encoder = this.encoder.Clone()
+	for _, field := range fields {
+		field.AddTo(clone.encoder)
+	}
+
+	return &clone
+}
+
+// Check - 检查级别
+func (this *logSinkCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if this.Enabled(entry.Level) { return checked.AddCore(entry, this) }
+	return checked
+}
+
+// Write - 编码并写入缓冲队列
+func (this *logSinkCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+
+	buffer, err := this.encoder.EncodeEntry(entry, fields)
+	if err != nil { return err }
+
+	line := bytes.TrimRight(buffer.Bytes(), "\n")
+	this.sink.push(logSinkMessage{level: entry.Level, time: entry.Time, line: bytes.Clone(line)})
+	buffer.Free()
+
+	return nil
+}
+
+// Sync - 异步发送，无需同步
+func (this *logSinkCore) Sync() error { return nil }
+
+// logSinkConn - 自动重连的网络连接（只在发送协程中使用）
+type logSinkConn struct {
+	network string
+	addr    string
+	timeout time.Duration
+	conn    net.Conn
+	// 对端是否已关闭连接（TCP 由后台读取协程检测）
+	broken  *atomic.Bool
+}
+
+// write - 发送数据，失败时重连并重试一次
+func (this *logSinkConn) write(data []byte) (err error) {
+
+	// 对端已关闭时先重连，否则第一次写入可能成功但数据被丢弃
+	if this.conn != nil && this.broken != nil && this.broken.Load() { this.close() }
+
+	for attempt := 0; attempt < 2; attempt++ {
+
+		if this.conn == nil {
+			if err = this.dial(); err != nil { return err }
+		}
+
+		_ = this.conn.SetWriteDeadline(time.Now().Add(this.timeout))
+		if _, err = this.conn.Write(data); err == nil { return nil }
+
+		this.close()
+	}
+
+	return err
+}
+
+// dial - 建立连接
+func (this *logSinkConn) dial() (err error) {
+
+	conn, err := net.DialTimeout(this.network, this.addr, this.timeout)
+	if err != nil { return err }
+
+	this.conn   = conn
+	this.broken = nil
+
+	// 远程输出的对端不会发送数据，读取返回即表示连接已断开
+	if this.network != "udp" {
+		broken := new(atomic.Bool)
+		this.broken = broken
+		go func() {
+			_, _ = io.Copy(io.Discard, conn)
+			broken.Store(true)
+		}()
+	}
+
+	return nil
+}
+
+// close - 关闭连接
+func (this *logSinkConn) close() {
+	if this.conn != nil { _ = this.conn.Close() }
+	this.conn   = nil
+	this.broken = nil
+}
+
+// logSinkTCP - TCP JSON 流（每行一条）
+type logSinkTCP struct {
+	conn *logSinkConn
+}
+
+func (this *logSinkTCP) write(items []logSinkMessage) (err error) {
+
+	var buffer bytes.Buffer
+	for _, item := range items {
+		buffer.Write(item.line)
+		buffer.WriteByte('\n')
+	}
+
+	return this.conn.write(buffer.Bytes())
+}
+
+func (this *logSinkTCP) close() { this.conn.close() }
+
+// logSyslog - RFC5424 syslog（UDP 每条一个数据报，TCP 使用 RFC6587 八位组计数分帧）
+type logSyslog struct {
+	conn     *logSinkConn
+	hostname string
+	app      string
+	pid      int
+}
+
+// newLogSyslog - 创建 syslog 输出
+func newLogSyslog(config dto.LogSinkConfig, timeout time.Duration) *logSyslog {
+
+	hostname, _ := os.Hostname()
+	if utils.Is.Empty(hostname) { hostname = "-" }
+
+	app := config.Tag
+	if utils.Is.Empty(app) { app = filepath.Base(os.Args[0]) }
+
+	return &logSyslog{
+		conn:     &logSinkConn{network: config.Network, addr: config.Addr, timeout: timeout},
+		hostname: hostname,
+		app:      app,
+		pid:      os.Getpid(),
+	}
+}
+
+func (this *logSyslog) write(items []logSinkMessage) (err error) {
+
+	for _, item := range items {
+
+		// PRI = facility(user = 1) * 8 + severity
+		message := fmt.Sprintf("<%d>1 %s %s %s %d - - %s", 8 + this.severity(item.level),
+			item.time.Format("2006-01-02T15:04:05.000000Z07:00"), this.hostname, this.app, this.pid, item.line)
+
+		if this.conn.network != "udp" {
+			message = fmt.Sprintf("%d %s", len(message), message)
+		}
+
+		if fail := this.conn.write([]byte(message)); fail != nil { err = fail }
+	}
+
+	return err
+}
+
+func (this *logSyslog) close() { this.conn.close() }
+
+// severity - zap 级别转换为 syslog 严重程度
+func (this *logSyslog) severity(level zapcore.Level) int {
+	switch {
+	case level <= zapcore.DebugLevel:
+		return 7
+	case level == zapcore.InfoLevel:
+		return 6
+	case level == zapcore.WarnLevel:
+		return 4
+	case level == zapcore.ErrorLevel:
+		return 3
+	default:
+		return 2
+	}
+}
+
+// logSinkHTTP - 批量 POST JSON Lines，失败时指数退避重试
+type logSinkHTTP struct {
+	url     string
+	headers map[string]string
+	retry   int
+	client  *http.Client
+}
+
+func (this *logSinkHTTP) write(items []logSinkMessage) (err error) {
+
+	var buffer bytes.Buffer
+	for _, item := range items {
+		buffer.Write(item.line)
+		buffer.WriteByte('\n')
+	}
+
+	for attempt := 0; attempt <= this.retry; attempt++ {
+
+		if attempt > 0 { time.Sleep(logSinkBackoff(attempt)) }
+
+		if err = this.post(buffer.Bytes()); err == nil { return nil }
+	}
+
+	return err
+}
+
+// logSinkBackoff - 第 attempt 次重试前的退避时间（200ms 起翻倍，最长 10s）
+func logSinkBackoff(attempt int) time.Duration {
+	return min(200 * time.Millisecond << min(attempt - 1, 6), 10 * time.Second)
+}
+
+// post - 发送一批日志
+func (this *logSinkHTTP) post(body []byte) (err error) {
+
+	request, err := http.NewRequest(http.MethodPost, this.url, bytes.NewReader(body))
+	if err != nil { return err }
+
+	request.Header.Set("Content-Type", "application/x-ndjson")
+	for key, value := range this.headers {
+		request.Header.Set(key, value)
+	}
+
+	response, err := this.client.Do(request)
+	if err != nil { return err }
+	defer response.Body.Close()
+
+	_, _ = io.Copy(io.Discard, response.Body)
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("日志发送失败: %s", response.Status)
+	}
+
+	return nil
+}
+
+func (this *logSinkHTTP) close() { this.client.CloseIdleConnections() }
@@ -0,0 +1,241 @@
+package facade
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/inis-io/aide/dto"
+)
+
+// newSinkLog - 创建只写入临时目录和远程输出的独立日志
+func newSinkLog(t *testing.T, sinks ...dto.LogSinkConfig) LogAPI {
+
+	t.Helper()
+
+	logger := LogInst.newWithConfig(dto.LogConfig{Enable: true, Dir: t.TempDir(), Sinks: sinks})
+	t.Cleanup(func() { _ = logger.Close() })
+
+	return logger
+}
+
+// sinkStats - 独立日志的远程输出统计
+func sinkStats(logger LogAPI) (stats []LogSinkStats) {
+	for _, sink := range logger.(*log).current().sinks {
+		stats = append(stats, sink.stats())
+	}
+	return stats
+}
+
+func TestLogSinkSyslogUDP(t *testing.T) {
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil { t.Fatal(err) }
+	defer conn.Close()
+
+	logger := newSinkLog(t, dto.LogSinkConfig{Type: "syslog", Network: "udp", Addr: conn.LocalAddr().String(), Tag: "aide"})
+	logger.Warn(map[string]any{"id": 1001}, "slow query")
+	if err := logger.Flush(); err != nil { t.Fatal(err) }
+
+	buffer := make([]byte, 4096)
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := conn.ReadFrom(buffer)
+	if err != nil { t.Fatal(err) }
+
+	// PRI = user(1) * 8 + warning(4)
+	pattern := regexp.MustCompile(`^<12>1 \d{4}-\d{2}-\d{2}T\S+ \S+ aide \d+ - - \{.*"msg":"slow query".*\}$`)
+	if message := string(buffer[:n]); !pattern.MatchString(message) {
+		t.Fatalf("unexpected syslog message: %q", message)
+	}
+}
+
+func TestLogSinkSyslogTCPFraming(t *testing.T) {
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil { t.Fatal(err) }
+	defer listener.Close()
+
+	received := make(chan []string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil { return }
+		defer conn.Close()
+
+		// RFC6587 八位组计数：MSG-LEN SP SYSLOG-MSG
+		var messages []string
+		reader := bufio.NewReader(conn)
+		for len(messages) < 2 {
+			size, err := reader.ReadString(' ')
+			if err != nil { break }
+			length, err := strconv.Atoi(strings.TrimSpace(size))
+			if err != nil { break }
+			message := make([]byte, length)
+			if _, err = io.ReadFull(reader, message); err != nil { break }
+			messages = append(messages, string(message))
+		}
+		received <- messages
+	}()
+
+	logger := newSinkLog(t, dto.LogSinkConfig{Type: "syslog", Network: "tcp", Addr: listener.Addr().String(), Tag: "aide"})
+	logger.Info(nil, "first")
+	logger.Error(nil, "second")
+	if err := logger.Flush(); err != nil { t.Fatal(err) }
+
+	select {
+	case messages := <-received:
+		if len(messages) != 2 { t.Fatalf("expected 2 framed messages, got %q", messages) }
+		if !strings.HasPrefix(messages[0], "<14>1 ") || !strings.Contains(messages[0], `"msg":"first"`) {
+			t.Fatalf("unexpected first message: %q", messages[0])
+		}
+		if !strings.HasPrefix(messages[1], "<11>1 ") || !strings.Contains(messages[1], `"msg":"second"`) {
+			t.Fatalf("unexpected second message: %q", messages[1])
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for syslog messages")
+	}
+}
+
+func TestLogSinkHTTPBatch(t *testing.T) {
+
+	var mutex sync.Mutex
+	var bodies []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.Header.Get("Content-Type") != "application/x-ndjson" || request.Header.Get("X-Token") != "secret" {
+			writer.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body, _ := io.ReadAll(request.Body)
+		mutex.Lock()
+		bodies = append(bodies, string(body))
+		mutex.Unlock()
+	}))
+	defer server.Close()
+
+	logger := newSinkLog(t, dto.LogSinkConfig{
+		Type:     "http",
+		Addr:     server.URL,
+		Batch:    2,
+		Interval: 60000,
+		Headers:  map[string]string{"X-Token": "secret"},
+	})
+	for i := 0; i < 5; i++ {
+		logger.Info(map[string]any{"index": i}, "batch")
+	}
+	if err := logger.Flush(); err != nil { t.Fatal(err) }
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	lines := 0
+	for _, body := range bodies {
+		count := strings.Count(body, "\n")
+		if count == 0 || count > 2 { t.Fatalf("unexpected batch size %d: %q", count, body) }
+		lines += count
+	}
+	if lines != 5 || len(bodies) != 3 { t.Fatalf("expected 5 lines in 3 batches, got %d lines in %d batches", lines, len(bodies)) }
+
+	if stats := sinkStats(logger); stats[0].Sent != 5 || stats[0].Failed != 0 {
+		t.Fatalf("unexpected stats: %+v", stats[0])
+	}
+}
+
+func TestLogSinkHTTPRetry(t *testing.T) {
+
+	var mutex sync.Mutex
+	attempts := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if attempts++; attempts == 1 {
+			writer.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	logger := newSinkLog(t, dto.LogSinkConfig{Type: "http", Addr: server.URL, Interval: 60000})
+	logger.Info(nil, "retry")
+	if err := logger.Flush(); err != nil { t.Fatal(err) }
+
+	if stats := sinkStats(logger); stats[0].Sent != 1 || stats[0].Failed != 0 {
+		t.Fatalf("unexpected stats: %+v", stats[0])
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if attempts != 2 { t.Fatalf("expected 2 attempts, got %d", attempts) }
+}
+
+func TestLogSinkTCPReconnect(t *testing.T) {
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil { t.Fatal(err) }
+	defer listener.Close()
+
+	lines := make(chan string, 2)
+	go func() {
+		// 每个连接只读取一行后关闭，第二行必须通过新连接送达
+		for i := 0; i < 2; i++ {
+			conn, err := listener.Accept()
+			if err != nil { return }
+			line, _ := bufio.NewReader(conn).ReadString('\n')
+			_ = conn.Close()
+			lines <- line
+		}
+	}()
+
+	read := func() string {
+		select {
+		case line := <-lines:
+			return line
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting for tcp line")
+			return ""
+		}
+	}
+
+	logger := newSinkLog(t, dto.LogSinkConfig{Type: "tcp", Addr: listener.Addr().String()})
+
+	logger.Info(nil, "first")
+	if err := logger.Flush(); err != nil { t.Fatal(err) }
+	if line := read(); !strings.Contains(line, `"msg":"first"`) { t.Fatalf("unexpected line: %q", line) }
+
+	// 等待发送端感知到对端关闭
+	time.Sleep(100 * time.Millisecond)
+
+	logger.Info(nil, "second")
+	if err := logger.Flush(); err != nil { t.Fatal(err) }
+	if line := read(); !strings.Contains(line, `"msg":"second"`) { t.Fatalf("unexpected line: %q", line) }
+}
+
+func TestLogSinkClose(t *testing.T) {
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil { t.Fatal(err) }
+	defer listener.Close()
+
+	logger := LogInst.newWithConfig(dto.LogConfig{Enable: true, Dir: t.TempDir(), Sinks: []dto.LogSinkConfig{
+		{Type: "tcp", Addr: listener.Addr().String()},
+	}})
+	if err := logger.Close(); err != nil { t.Fatal(err) }
+
+	// 关闭后发送协程已退出，新的日志计入 Dropped
+	sink := logger.(*log).sinks[0]
+	select {
+	case <-sink.stopped:
+	default:
+		t.Fatal("sink goroutine still running after Close")
+	}
+
+	logger.Info(nil, "after close")
+	if stats := sinkStats(logger); stats[0].Dropped != 1 { t.Fatalf("unexpected stats: %+v", stats[0]) }
+}
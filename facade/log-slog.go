@@ -0,0 +1,298 @@
+package facade
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/inis-io/aide/dto"
+	"github.com/spf13/cast"
+	"go.uber.org/zap/zapcore"
+)
+
+// ============================ slog -> facade.Log ============================
+
+// LogSlogHandler - 把 log/slog 的日志写入 facade.Log
+/**
+ * 级别映射：< Info 写入 debug，< Warn 写入 info，< Error 写入 warn，其余写入 error；
+ * 分组（WithGroup / slog.Group）转换为嵌套对象，上下文中的链路 ID 与请求级字段会一并写入；
+ * 日志时间使用 slog.Record 的时间，AddSource 为 true 时输出调用位置。
+ * @example：
+ * handler := facade.LogInst.NewSlogHandler()
+ * handler.AddSource = true
+ * slog.SetDefault(slog.New(handler))
+ * slog.Info("create order", "id", 1001, slog.Group("user", "id", 7))
+ */
+type LogSlogHandler struct {
+	// 是否输出调用位置（caller），与 slog.HandlerOptions.AddSource 对应
+	AddSource bool
+	// 目标日志，为空时使用当前的 facade.Log（重新 Init 后自动跟随）
+	logger LogAPI
+	// 通过 WithAttrs / WithGroup 累积的属性
+	attrs  []logSlogAttr
+	// 当前分组
+	groups []string
+}
+
+// logSlogAttr - 带分组路径的属性
+type logSlogAttr struct {
+	groups []string
+	attr   slog.Attr
+}
+
+// NewSlogHandler - 创建写入 facade.Log 的 slog.Handler
+/**
+ * @param logger 目标日志，不传时使用当前的 facade.Log
+ */
+func (this *LogClass) NewSlogHandler(logger ...LogAPI) *LogSlogHandler {
+
+	item := &LogSlogHandler{}
+	if len(logger) > 0 { item.logger = logger[0] }
+
+	return item
+}
+
+// target - 当前的目标日志
+func (this *LogSlogHandler) target() LogAPI {
+
+	if this.logger != nil { return this.logger }
+
+	LogInst.ensureLog()
+	return Log
+}
+
+// Enabled - 是否输出该级别的日志
+func (this *LogSlogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+
+	item, ok := this.target().(*log)
+	if !ok { return true }
+
+	return item.enabled(this.level(level))
+}
+
+// Handle - 写入一条日志
+func (this *LogSlogHandler) Handle(ctx context.Context, record slog.Record) error {
+
+	data := make(map[string]any, record.NumAttrs() + len(this.attrs))
+
+	for _, item := range this.attrs {
+		this.put(data, item.groups, item.attr)
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		this.put(data, this.groups, attr)
+		return true
+	})
+
+	logger := this.target()
+	if ctx != nil { logger = logger.WithContext(ctx) }
+
+	// 保留记录中的时间与调用位置
+	if item, ok := logger.(*log); ok {
+		var pc uintptr
+		if this.AddSource { pc = record.PC }
+		item.entry(this.level(record.Level), record.Time, pc, data, record.Message)
+		return nil
+	}
+
+	switch this.level(record.Level) {
+	case zapcore.DebugLevel:
+		logger.Debug(data, record.Message)
+	case zapcore.InfoLevel:
+		logger.Info(data, record.Message)
+	case zapcore.WarnLevel:
+		logger.Warn(data, record.Message)
+	default:
+		logger.Error(data, record.Message)
+	}
+
+	return nil
+}
+
+// WithAttrs - 创建携带固定属性的 Handler
+func (this *LogSlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+
+	if len(attrs) == 0 { return this }
+
+	clone := *this
+	clone.attrs = make([]logSlogAttr, 0, len(this.attrs) + len(attrs))
+	clone.attrs = append(clone.attrs, this.attrs...)
+	for _, attr := range attrs {
+		clone.attrs = append(clone.attrs, logSlogAttr{groups: this.groups, attr: attr})
+	}
+
+	return &clone
+}
+
+// WithGroup - 创建后续属性都放在分组下的 Handler
+func (this *LogSlogHandler) WithGroup(name string) slog.Handler {
+
+	if name == "" { return this }
+
+	clone := *this
+	clone.groups = append(this.groups[:len(this.groups):len(this.groups)], name)
+
+	return &clone
+}
+
+// level - slog 级别转换为 zap 级别
+func (this *LogSlogHandler) level(level slog.Level) zapcore.Level {
+	switch {
+	case level < slog.LevelInfo:
+		return zapcore.DebugLevel
+	case level < slog.LevelWarn:
+		return zapcore.InfoLevel
+	case level < slog.LevelError:
+		return zapcore.WarnLevel
+	default:
+		return zapcore.ErrorLevel
+	}
+}
+
+// put - 按分组路径写入属性
+func (this *LogSlogHandler) put(data map[string]any, groups []string, attr slog.Attr) {
+
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) { return }
+
+	for _, group := range groups {
+		child, ok := data[group].(map[string]any)
+		if !ok {
+			child = map[string]any{}
+			data[group] = child
+		}
+		data = child
+	}
+
+	if attr.Value.Kind() != slog.KindGroup {
+		data[attr.Key] = this.value(attr.Value)
+		return
+	}
+
+	items := attr.Value.Group()
+	if len(items) == 0 { return }
+
+	// 空键的分组直接展开到当前层级
+	var path []string
+	if attr.Key != "" { path = []string{attr.Key} }
+
+	for _, item := range items {
+		this.put(data, path, item)
+	}
+}
+
+// value - slog 值转换为普通值
+func (this *LogSlogHandler) value(value slog.Value) any {
+	switch value.Kind() {
+	case slog.KindDuration:
+		return value.Duration().String()
+	case slog.KindTime:
+		return value.Time().Format(time.RFC3339Nano)
+	}
+
+	// 嵌套在分组中的 error 会被编码为 {}，统一转换为错误信息
+	if err, ok := value.Any().(error); ok { return err.Error() }
+
+	return value.Any()
+}
+
+// ============================ slog.Handler -> LogAPI ============================
+
+// logSlog - 由 slog.Handler 实现的 LogAPI
+type logSlog struct {
+	handler slog.Handler
+}
+
+// FromSlog - 使用任意 slog.Handler 创建 LogAPI
+/**
+ * @example：
+ * logger := facade.LogInst.FromSlog(slog.NewJSONHandler(os.Stdout, nil))
+ * logger.Info(map[string]any{"id": 1001}, "create order")
+ */
+func (this *LogClass) FromSlog(handler slog.Handler) LogAPI {
+	return &logSlog{handler: handler}
+}
+
+// NewLog - 使用自定义配置创建新的日志实例（写入文件）
+func (this *logSlog) NewLog(config dto.LogConfig) LogAPI {
+	return LogInst.newWithConfig(config)
+}
+
+func (this *logSlog) Info(data map[string]any, msg ...any) {
+	this.write(slog.LevelInfo, data, msg...)
+}
+
+func (this *logSlog) Warn(data map[string]any, msg ...any) {
+	this.write(slog.LevelWarn, data, msg...)
+}
+
+func (this *logSlog) Error(data map[string]any, msg ...any) {
+	this.write(slog.LevelError, data, msg...)
+}
+
+func (this *logSlog) Debug(data map[string]any, msg ...any) {
+	this.write(slog.LevelDebug, data, msg...)
+}
+
+// With - 创建携带固定字段的子日志
+func (this *logSlog) With(fields map[string]any) LogAPI {
+
+	if len(fields) == 0 { return this }
+
+	return &logSlog{handler: this.handler.WithAttrs(this.attrs(LogInst.redactor().apply(fields)))}
+}
+
+// WithContext - 创建携带上下文字段（链路 ID、请求级字段）的子日志
+func (this *logSlog) WithContext(ctx context.Context) LogAPI {
+	return this.With(LogInst.traceFields(ctx))
+}
+
+// Named - 创建模块日志（附带 logger 字段）
+func (this *logSlog) Named(name string) LogAPI {
+	return this.With(map[string]any{"logger": name})
+}
+
+// Flush - slog.Handler 自行管理输出，无需处理
+func (this *logSlog) Flush() error { return nil }
+
+// Close - slog.Handler 自行管理输出，无需处理
+func (this *logSlog) Close() error { return nil }
+
+// write - 统一日志写入实现
+func (this *logSlog) write(level slog.Level, data map[string]any, msg ...any) {
+
+	ctx := context.Background()
+	if this.handler == nil || !this.handler.Enabled(ctx, level) { return }
+
+	content := strings.ToLower(level.String())
+	if len(msg) > 0 && cast.ToString(msg[0]) != "" { content = cast.ToString(msg[0]) }
+
+	// 跳过 runtime.Callers、write 和 Info 等方法，记录业务代码的调用位置
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:])
+
+	// 与 facade.Log 使用同一套脱敏规则
+	record := slog.NewRecord(time.Now(), level, content, pcs[0])
+	record.AddAttrs(this.attrs(LogInst.redactor().apply(data))...)
+
+	_ = this.handler.Handle(ctx, record)
+}
+
+// attrs - 字段转换为按键排序的属性
+func (this *logSlog) attrs(data map[string]any) []slog.Attr {
+
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	attrs := make([]slog.Attr, 0, len(keys))
+	for _, key := range keys {
+		attrs = append(attrs, slog.Any(key, data[key]))
+	}
+
+	return attrs
+}
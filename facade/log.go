@@ -1,9 +1,13 @@
 package facade
 
 import (
-	"fmt"
+	"context"
+	"io"
+	"os"
+	"runtime"
 	"sort"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/inis-io/aide/dto"
@@ -11,7 +15,6 @@ import (
 	"github.com/spf13/cast"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
-	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 // LogInst - 日志配置控制器实例
@@ -29,6 +32,8 @@ type LogClass struct {
 	Level     string        `json:"level"`
 	// 日志内容
 	Msg       string        `json:"msg"`
+	// 日志级别（重新 Init 后保留运行时的修改）
+	levels    *logLevels
 }
 
 func init() { LogInst.Init() }
@@ -39,6 +44,17 @@ func (this *LogClass) normConfig(config dto.LogConfig) dto.LogConfig {
 	if config.Size    <= 0 { config.Size = 2 }
 	if config.Age     <= 0 { config.Age  = 7 }
 	if config.Backups <= 0 { config.Backups = 20 }
+	if utils.Is.Empty(config.Dir)    { config.Dir    = "runtime/logs" }
+	if utils.Is.Empty(config.Format) { config.Format = "json" }
+	if utils.Is.Empty(config.Level)  { config.Level  = "debug" }
+	if config.Async.Size          <= 0 { config.Async.Size          = 256 * 1024 }
+	if config.Async.Interval      <= 0 { config.Async.Interval      = 1000 }
+	if config.Sampling.Initial    <= 0 { config.Sampling.Initial    = 100 }
+	if config.Sampling.Thereafter <= 0 { config.Sampling.Thereafter = 100 }
+
+	config.Console = strings.ToLower(strings.TrimSpace(config.Console))
+	config.Format  = strings.ToLower(strings.TrimSpace(config.Format))
+	config.Level   = strings.ToLower(strings.TrimSpace(config.Level))
 
 	// 仅当是空配置时启用默认值，避免覆盖调用方显式关闭日志。
 	if !config.Enable && config.Hash == "" && config.Size == 2 && config.Age == 7 && config.Backups == 20 {
@@ -107,30 +123,50 @@ func (this *LogClass) setActiveLog(config dto.LogConfig) {
 	conf := LogInst.normConfig(config)
 	this.Config = conf
 
-	LogInfo  = this.NewLevel("info", conf)
-	LogWarn  = this.NewLevel("warn", conf)
-	LogError = this.NewLevel("error", conf)
-	LogDebug = this.NewLevel("debug", conf)
-
-	Log = &log{
-		Config:      conf,
-		InfoLogger:  LogInfo,
-		WarnLogger:  LogWarn,
-		ErrorLogger: LogError,
-		DebugLogger: LogDebug,
-	}
+	// 级别只按配置的变化更新，运行时通过 SetLevel 修改的级别不会丢失
+	var previous dto.LogConfig
+	if item := logActive.Load(); item != nil { previous = item.Config }
+	if this.levels == nil { this.levels = newLogLevels(dto.LogConfig{}) }
+	this.levels.reload(previous, conf)
+
+	item := this.newLog(conf, this.levels)
+
+	LogInfo  = item.InfoLogger
+	LogWarn  = item.WarnLogger
+	LogError = item.ErrorLogger
+	LogDebug = item.DebugLogger
+
+	// 之前创建的子日志（With / Named / WithContext）会自动写入新实例
+	item.shared = true
+	old := logActive.Swap(item)
+	Log = item
+
+	// 落盘并关闭旧实例的输出，远程输出队列中剩余的日志会继续发送完
+	if old != nil { old.release() }
 }
 
 // newWithConfig - 使用传入配置创建新的日志实例
 func (this *LogClass) newWithConfig(config dto.LogConfig) LogAPI {
 	conf := LogInst.normConfig(config)
-	return &log{
-		Config:      conf,
-		InfoLogger:  this.NewLevel("info", conf),
-		WarnLogger:  this.NewLevel("warn", conf),
-		ErrorLogger: this.NewLevel("error", conf),
-		DebugLogger: this.NewLevel("debug", conf),
+	return this.newLog(conf, newLogLevels(conf))
+}
+
+// newLog - 按配置创建日志实例
+func (this *LogClass) newLog(conf dto.LogConfig, levels *logLevels) *log {
+
+	item := &log{
+		Config: conf,
+		levels: levels,
+		redact: newLogRedactor(conf.Redact),
+		sinks:  newLogSinks(conf.Sinks),
 	}
+
+	item.InfoLogger  = item.newChannel("info")
+	item.WarnLogger  = item.newChannel("warn")
+	item.ErrorLogger = item.newChannel("error")
+	item.DebugLogger = item.newChannel("debug")
+
+	return item
 }
 
 // ensureLog - 保证默认日志实例可用
@@ -176,14 +212,24 @@ func (this *LogClass) NewLevel(levelName string, config ...dto.LogConfig) *zap.L
 		conf = LogInst.normConfig(conf)
 	}
 
-	path := fmt.Sprintf("runtime/logs/%s/%s.log", time.Now().Format("2006-01-02"), levelName)
+	logger, _ := this.newLevel(levelName, conf)
+	return logger
+}
+
+// newLevel - 创建日志通道，同时返回底层输出（用于落盘和关闭）
+func (this *LogClass) newLevel(levelName string, conf dto.LogConfig) (*zap.Logger, *logOutput) {
 
-	write := zapcore.AddSync(&lumberjack.Logger{
-		Filename:   path,
-		MaxAge:     conf.Age,
-		MaxSize:    conf.Size,
-		MaxBackups: conf.Backups,
-	})
+	// 按当前日期写入 <Dir>/<日期>/<级别>.log，跨天自动切换目录
+	output := &logOutput{daily: newLogDaily(levelName, conf)}
+
+	// 异步模式先写入内存缓冲，写满或到达刷新间隔时落盘
+	if conf.Async.Enable {
+		output.buffer = &zapcore.BufferedWriteSyncer{
+			WS:            output.daily,
+			Size:          conf.Async.Size,
+			FlushInterval: time.Duration(conf.Async.Interval) * time.Millisecond,
+		}
+	}
 
 	encoder := func() zapcore.Encoder {
 
@@ -192,6 +238,10 @@ func (this *LogClass) NewLevel(levelName string, config ...dto.LogConfig) *zap.L
 		encoderConfig.EncodeTime = zapcore.TimeEncoderOfLayout("2006-01-02 15:04:05")
 		encoderConfig.EncodeCaller = zapcore.ShortCallerEncoder
 
+		if conf.Format == "console" {
+			return zapcore.NewConsoleEncoder(encoderConfig)
+		}
+
 		return zapcore.NewJSONEncoder(encoderConfig)
 	}
 
@@ -199,9 +249,17 @@ func (this *LogClass) NewLevel(levelName string, config ...dto.LogConfig) *zap.L
 	if err := level.UnmarshalText([]byte(levelName)); err != nil {
 		_ = level.UnmarshalText([]byte("info"))
 	}
-	core := zapcore.NewCore(encoder(), write, level)
+	core := zapcore.NewCore(encoder(), output, level)
+
+	// 同时输出到控制台（终端和管道不支持 fsync，隐藏 Sync 避免落盘时返回错误）
+	switch conf.Console {
+	case "stdout":
+		core = zapcore.NewTee(core, zapcore.NewCore(encoder(), zapcore.Lock(zapcore.AddSync(struct{ io.Writer }{os.Stdout})), level))
+	case "stderr":
+		core = zapcore.NewTee(core, zapcore.NewCore(encoder(), zapcore.Lock(zapcore.AddSync(struct{ io.Writer }{os.Stderr})), level))
+	}
 
-	return zap.New(core)
+	return zap.New(core), output
 }
 
 // LogInfo - info日志通道
@@ -223,6 +281,11 @@ type LogAPI interface {
 	Error(data map[string]any, msg ...any)
 	Debug(data map[string]any, msg ...any)
 	NewLog(config dto.LogConfig) LogAPI
+	With(fields map[string]any) LogAPI
+	WithContext(ctx context.Context) LogAPI
+	Named(name string) LogAPI
+	Flush() error
+	Close() error
 }
 
 // log - 日志结构体
@@ -232,60 +295,153 @@ type log struct {
 	WarnLogger  *zap.Logger
 	ErrorLogger *zap.Logger
 	DebugLogger *zap.Logger
+	// 子日志的固定字段
+	fields      map[string]any
+	// 模块名称
+	module      string
+	// 日志级别（同一个根日志的所有子日志共享）
+	levels      *logLevels
+	// 脱敏器（未启用时为 nil）
+	redact      *logRedactor
+	// 远程输出
+	sinks       logSinks
+	// 本地输出（落盘和关闭用）
+	outputs     []*logOutput
+	// 根实例（子日志写入根实例的输出，为 nil 时表示自身就是根实例）
+	root        *log
+	// 是否跟随 facade.Log（重新 Init 后写入新的活动实例）
+	shared      bool
+	// 模块通道缓存
+	named       *atomic.Pointer[logNamed]
+}
+
+// logNamed - 模块通道缓存（根实例切换后重新创建）
+type logNamed struct {
+	root     *log
+	channels map[zapcore.Level]*zap.Logger
 }
 
 // Log - 日志
 var Log LogAPI
 
+// logActive - 当前活动的根实例（facade.Log 及其子日志实际写入的实例）
+var logActive atomic.Pointer[log]
+
 // NewLog - 使用自定义配置创建新的日志实例
 func (this *log) NewLog(config dto.LogConfig) LogAPI {
 	return LogInst.newWithConfig(config)
 }
 
 func (this *log) Info(data map[string]any, msg ...any) {
-	this.write(this.InfoLogger, "info", data, msg...)
+	this.write(zapcore.InfoLevel, data, msg...)
 }
 
 func (this *log) Warn(data map[string]any, msg ...any) {
-	this.write(this.WarnLogger, "warn", data, msg...)
+	this.write(zapcore.WarnLevel, data, msg...)
 }
 
 func (this *log) Error(data map[string]any, msg ...any) {
-	this.write(this.ErrorLogger, "error", data, msg...)
+	this.write(zapcore.ErrorLevel, data, msg...)
 }
 
 func (this *log) Debug(data map[string]any, msg ...any) {
-	this.write(this.DebugLogger, "debug", data, msg...)
+	this.write(zapcore.DebugLevel, data, msg...)
 }
 
 // write - 统一日志写入实现
-func (this *log) write(logger *zap.Logger, level string, data map[string]any, msg ...any) {
-	if !this.Config.Enable {
-		return
-	}
+func (this *log) write(level zapcore.Level, data map[string]any, msg ...any) {
+	this.entry(level, time.Time{}, 0, data, msg...)
+}
+
+// entry - 写入一条日志
+/**
+ * @param at 日志时间，为零值时使用当前时间
+ * @param pc 调用位置，为 0 时不输出 caller
+ */
+func (this *log) entry(level zapcore.Level, at time.Time, pc uintptr, data map[string]any, msg ...any) {
+
+	root := this.current()
+	if !root.Config.Enable || !root.levels.enabled(this.module, level) { return }
 
 	if len(msg) == 0 {
-		msg = append(msg, level)
+		msg = append(msg, level.String())
 	}
 
 	content := cast.ToString(msg[0])
 	if strings.TrimSpace(content) == "" {
-		content = level
+		content = level.String()
 	}
 
-	fields := this.mapFields(data)
-	logger = this.ensureLogger(logger)
+	// 先检查级别和采样，被丢弃的日志无需脱敏和转换字段
+	checked := this.ensureLogger(this.channel(root, level)).Check(level, content)
+	if checked == nil { return }
 
+	if !at.IsZero() { checked.Time = at }
+	if pc != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+		checked.Caller = zapcore.EntryCaller{Defined: true, PC: pc, File: frame.File, Line: frame.Line, Function: frame.Function}
+	}
+
+	checked.Write(root.mapFields(this.merge(data))...)
+}
+
+// current - 实际写入的根实例（跟随 facade.Log 的日志始终写入当前活动实例）
+func (this *log) current() *log {
+
+	if this.shared {
+		if item := logActive.Load(); item != nil { return item }
+	}
+	if this.root != nil { return this.root }
+
+	return this
+}
+
+// derive - 创建子日志（子日志不持有输出，写入时使用根实例的输出）
+func (this *log) derive() *log {
+
+	clone := *this
+	if clone.root == nil { clone.root = this }
+
+	return &clone
+}
+
+// enabled - 是否输出该级别的日志
+func (this *log) enabled(level zapcore.Level) bool {
+	root := this.current()
+	return root.Config.Enable && root.levels.enabled(this.module, level)
+}
+
+// channel - 根实例对应级别的通道，模块日志按根实例缓存带模块名称的通道
+func (this *log) channel(root *log, level zapcore.Level) *zap.Logger {
+
+	var logger *zap.Logger
 	switch level {
-	case "warn":
-		logger.Warn(content, fields...)
-	case "error":
-		logger.Error(content, fields...)
-	case "debug":
-		logger.Debug(content, fields...)
+	case zapcore.WarnLevel:
+		logger = root.WarnLogger
+	case zapcore.ErrorLevel:
+		logger = root.ErrorLogger
+	case zapcore.DebugLevel:
+		logger = root.DebugLogger
 	default:
-		logger.Info(content, fields...)
+		logger = root.InfoLogger
 	}
+
+	if utils.Is.Empty(this.module) || this.named == nil || logger == nil { return logger }
+
+	if cache := this.named.Load(); cache != nil && cache.root == root { return cache.channels[level] }
+
+	cache := &logNamed{root: root, channels: make(map[zapcore.Level]*zap.Logger, 4)}
+	for key, item := range map[zapcore.Level]*zap.Logger{
+		zapcore.InfoLevel:  root.InfoLogger,
+		zapcore.WarnLevel:  root.WarnLogger,
+		zapcore.ErrorLevel: root.ErrorLogger,
+		zapcore.DebugLevel: root.DebugLogger,
+	} {
+		if item != nil { cache.channels[key] = newLogLevelCore(item.Named(this.module), root.levels, this.module) }
+	}
+	this.named.Store(cache)
+
+	return cache.channels[level]
 }
 
 func (this *log) mapFields(data map[string]any) []zap.Field {
@@ -293,6 +449,9 @@ func (this *log) mapFields(data map[string]any) []zap.Field {
 		return nil
 	}
 
+	// 脱敏后再交给 zap，敏感信息不会写入任何输出
+	data = this.redact.apply(data)
+
 	keys := make([]string, 0, len(data))
 	for key := range data {
 		keys = append(keys, key)
@@ -26,13 +26,30 @@ type FileCacheClientItem struct {
 }
 
 type FileCacheClient struct {
-	dir    string     // 缓存目录
-	mutex  sync.Mutex // 互斥锁，用于保证并发安全
-	prefix string     // 缓存文件名前缀
-	expire int64      // 默认缓存过期时间
+	dir    string        // 缓存目录
+	mutex  sync.Mutex    // 互斥锁，用于保证并发安全
+	prefix string        // 缓存文件名前缀
+	expire int64         // 默认缓存过期时间
 	items  map[string]*FileCacheClientItem
+	done   chan struct{} // 停止定时器
+	once   sync.Once     // 保证只关闭一次
 }
 
+// FileCacheClientMeta - 缓存元数据（保存在缓存目录下的 .meta 子目录中，重启后用于重建索引）
+type FileCacheClientMeta struct {
+	// 原始 key
+	Key    string `json:"key"`
+	// 过期时间（秒，0 表示永不过期）
+	Expire int64  `json:"expire"`
+	// 写入时间戳
+	Start  int64  `json:"start"`
+	// 过期时间戳
+	End    int64  `json:"end"`
+}
+
+// fileCacheMetaDir - 元数据子目录，与缓存文件分开存放，避免以 .meta 结尾的 key 与元数据文件冲突
+const fileCacheMetaDir = ".meta"
+
 // NewFileCache - 新建文件缓存
 /**
  * @param dir 缓存目录
@@ -58,6 +75,12 @@ func NewFileCache(dir any, expire any, prefix ...any) (*FileCacheClient, error)
 		prefix: cast.ToString(prefix[0]),
 		items:  make(map[string]*FileCacheClientItem),
 		expire: cast.ToInt64(expire),
+		done:   make(chan struct{}),
+	}
+
+	// 从磁盘重建索引，重启后仍可读取之前写入的缓存
+	if err = client.load(); err != nil {
+		return nil, err
 	}
 
 	// 定时器 - 每隔一段时间清理过期的缓存文件
@@ -79,7 +102,7 @@ func (this *FileCacheClient) Get(key any) (result []byte) {
 
 	if time.Now().After(item.end) {
 		// 文件已过期，删除文件并返回false
-		err := os.Remove(item.name)
+		err := this.remove(item)
 		if err != nil {
 			return nil
 		}
@@ -108,7 +131,7 @@ func (this *FileCacheClient) Has(key any) (exist bool) {
 
 	if time.Now().After(item.end) {
 		// 文件已过期，删除文件并返回false
-		err := os.Remove(item.name)
+		err := this.remove(item)
 		if err != nil {
 			return false
 		}
@@ -180,19 +203,8 @@ func (this *FileCacheClient) SetE(key any, value []byte, expire int64) (err erro
 	}
 
 	name := this.name(cast.ToString(key))
-	file, err := os.Create(name)
-	if err != nil {
-		return fmt.Errorf("create cache file %s error: %v", name, err)
-	}
-
-	_, err = file.Write(value)
-	if err != nil {
-		return fmt.Errorf("write to cache file %s error: %v", name, err)
-	}
-
-	err = file.Close()
-	if err != nil {
-		return err
+	if path.Base(name) == fileCacheMetaDir {
+		return fmt.Errorf("cache key %v conflicts with the meta dir", key)
 	}
 
 	// end 过期时间，expire = 0 表示永不过期
@@ -203,13 +215,35 @@ func (this *FileCacheClient) SetE(key any, value []byte, expire int64) (err erro
 		end = time.Now().Add(time.Duration(expire) * time.Second)
 	}
 
-	this.items[cast.ToString(key)] = &FileCacheClientItem{
+	item := &FileCacheClientItem{
 		expire: expire,
 		name:   name,
 		start:  time.Now(),
 		end:    end,
 	}
 
+	// 先写入元数据，缓存文件写入失败时一并删除，不会留下没有元数据的缓存文件
+	meta := FileCacheClientMeta{
+		Key:    cast.ToString(key),
+		Expire: expire,
+		Start:  item.start.Unix(),
+		End:    item.end.Unix(),
+	}
+	if err = os.MkdirAll(path.Join(this.dir, fileCacheMetaDir), 0755); err != nil {
+		return fmt.Errorf("create cache meta dir error: %v", err)
+	}
+	if err = os.WriteFile(this.meta(name), []byte(Json.Encode(meta)), 0644); err != nil {
+		return fmt.Errorf("write cache meta %s error: %v", name, err)
+	}
+
+	if err = os.WriteFile(name, value, 0644); err != nil {
+		_ = this.remove(item)
+		delete(this.items, cast.ToString(key))
+		return fmt.Errorf("write to cache file %s error: %v", name, err)
+	}
+
+	this.items[cast.ToString(key)] = item
+
 	return nil
 }
 
@@ -224,7 +258,7 @@ func (this *FileCacheClient) DelE(key any) (err error) {
 		return nil
 	}
 
-	err = os.Remove(cacheItem.name)
+	err = this.remove(cacheItem)
 	if err != nil && !os.IsNotExist(err) {
 		return fmt.Errorf("delete cache file %s error: %v", cacheItem.name, err)
 	}
@@ -254,7 +288,7 @@ func (this *FileCacheClient) ClearE() (err error) {
 	defer this.mutex.Unlock()
 
 	for _, cacheItem := range this.items {
-		err := os.Remove(cacheItem.name)
+		err := this.remove(cacheItem)
 		if err != nil && !os.IsNotExist(err) {
 			return fmt.Errorf("delete cache file %s error: %v", cacheItem.name, err)
 		}
@@ -298,7 +332,7 @@ func (this *FileCacheClient) DelPrefixE(prefix ...any) (err error) {
 	for key, cacheItem := range this.items {
 		for _, value := range prefixes {
 			if strings.HasPrefix(key, value) {
-				err := os.Remove(cacheItem.name)
+				err := this.remove(cacheItem)
 				if err != nil && !os.IsNotExist(err) {
 					return fmt.Errorf("delete cache file %s error: %v", cacheItem.name, err)
 				}
@@ -358,7 +392,7 @@ func (this *FileCacheClient) DelTagsE(tag ...any) (err error) {
 		if !ok {
 			continue
 		}
-		err := os.Remove(item.name)
+		err := this.remove(item)
 		if err != nil && !os.IsNotExist(err) {
 			return fmt.Errorf("delete cache file %s error: %v", item.name, err)
 		}
@@ -423,17 +457,30 @@ func (this *FileCacheClient) name(key any) (result string) {
 	return path.Join(this.dir, cast.ToString(key))
 }
 
+// Close 停止定时清理，多次调用是安全的
+func (this *FileCacheClient) Close() {
+	this.once.Do(func() { close(this.done) })
+}
+
 // timer 定时器 - 每隔一段时间清理过期的缓存文件
 func (this *FileCacheClient) timer() {
+
+	ticker := time.NewTicker(1 * time.Second)
+	defer ticker.Stop()
+
 	for {
 
-		time.Sleep(1 * time.Second)
+		select {
+		case <-this.done:
+			return
+		case <-ticker.C:
+		}
 
 		this.mutex.Lock()
 		for key, item := range this.items {
 			if time.Now().After(item.end) {
 				// 文件已过期，删除文件并从缓存中删除
-				err := os.Remove(item.name)
+				err := this.remove(item)
 				if err != nil {
 					continue
 				}
@@ -443,3 +490,86 @@ func (this *FileCacheClient) timer() {
 		this.mutex.Unlock()
 	}
 }
+
+// remove 删除缓存文件及其元数据
+func (this *FileCacheClient) remove(item *FileCacheClientItem) (err error) {
+	_ = os.Remove(this.meta(item.name))
+	return os.Remove(item.name)
+}
+
+// meta 缓存文件对应的元数据文件
+func (this *FileCacheClient) meta(name string) string {
+	return path.Join(this.dir, fileCacheMetaDir, path.Base(name))
+}
+
+// load 扫描缓存目录重建索引
+/**
+ * 优先使用 .meta 子目录中的元数据；没有元数据的旧文件按文件名还原 key，并以修改时间加默认过期时间作为过期时间；
+ * 缓存文件已不存在的元数据会被删除
+ */
+func (this *FileCacheClient) load() (err error) {
+
+	entries, err := os.ReadDir(this.dir)
+	if err != nil {
+		return fmt.Errorf("read cache dir error: %v", err)
+	}
+
+	now := time.Now()
+
+	for _, entry := range entries {
+
+		base := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(base, this.prefix) {
+			continue
+		}
+
+		name := path.Join(this.dir, base)
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		meta := FileCacheClientMeta{
+			Key:    strings.TrimPrefix(base, this.prefix),
+			Expire: this.expire,
+			Start:  info.ModTime().Unix(),
+		}
+
+		if content, err := os.ReadFile(this.meta(name)); err == nil {
+			_ = Json.Unmarshal(content, &meta)
+		}
+
+		item := &FileCacheClientItem{
+			expire: meta.Expire,
+			name:   name,
+			start:  time.Unix(meta.Start, 0),
+		}
+
+		switch {
+		case meta.End > 0:
+			item.end = time.Unix(meta.End, 0)
+		case meta.Expire == 0:
+			item.end = item.start.AddDate(100, 0, 0)
+		default:
+			item.end = item.start.Add(time.Duration(meta.Expire) * time.Second)
+		}
+
+		// 已过期的直接清理
+		if now.After(item.end) {
+			_ = this.remove(item)
+			continue
+		}
+
+		this.items[meta.Key] = item
+	}
+
+	// 清理缓存文件已不存在的元数据
+	metas, _ := os.ReadDir(path.Join(this.dir, fileCacheMetaDir))
+	for _, entry := range metas {
+		if _, err := os.Stat(path.Join(this.dir, entry.Name())); os.IsNotExist(err) {
+			_ = os.Remove(path.Join(this.dir, fileCacheMetaDir, entry.Name()))
+		}
+	}
+
+	return nil
+}